@@ -6,9 +6,7 @@ import (
 	"os/exec"
 	"time"
 
-	"github.com/smtdfc/dix/generator"
 	"github.com/smtdfc/dix/helpers"
-	"github.com/smtdfc/dix/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -40,9 +38,9 @@ Example:
 			targetDir = args[1]
 		}
 
-		p := parser.NewParser()
-		g := generator.NewGenerator()
-		mt, err := p.Parse(targetDir)
+		p := newParser(config)
+		g := newGenerator(config)
+		mt, err := parse(p, config, targetDir)
 		if err != nil {
 			fatalDixError(err)
 		}
@@ -59,11 +57,8 @@ Example:
 			fatalDixError(err)
 		}
 
-		outputPath := "./generated/dix/root.go"
-		if config.Output != "" {
-			outputPath = config.Output
-		}
-		err = helpers.WriteTextFile(code, outputPath)
+		outputPath := config.OutputPath()
+		err = helpers.WriteGeneratedFile(code, outputPath, forceWrite)
 		if err != nil {
 			fatalDixError(err)
 		}
@@ -84,6 +79,7 @@ Example:
 }
 
 func init() {
+	buildCmd.Flags().BoolVar(&forceWrite, "force", false, "overwrite the output file even if it was not generated by dix")
 	rootCmd.AddCommand(buildCmd)
 
 }
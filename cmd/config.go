@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/smtdfc/dix/parser"
+)
+
+func newParser(config *helpers.Config) *parser.Parser {
+	p := parser.NewParser()
+	p.Logger = &parser.ConsoleLogger{Out: os.Stdout, Verbose: verbose}
+	p.FollowSymlinks = config.FollowSymlinks
+	p.Directives = config.Directives
+	p.Exclude = config.Exclude
+	return p
+}
+
+// parse scans dir, or every directory of the roots configuration key when it
+// is set.
+func parse(p *parser.Parser, config *helpers.Config, dir string) (*parser.Metadata, error) {
+	if len(config.Roots) == 0 {
+		return p.Parse(dir)
+	}
+	return p.ParseRoots(config.Roots, config.MergePolicy())
+}
+
+func newGenerator(config *helpers.Config) *generator.Generator {
+	g := generator.NewGenerator()
+	g.SourceComments = config.SourceComments
+	g.EmbedHash = config.EmbedHash
+	g.Format = config.Format
+	g.Registry = config.Registry
+	g.PackageName = config.PackageName
+	g.RootName = config.RootName
+	g.RootAlias = config.RootAlias
+	g.HoistSingletons = config.HoistSingletons
+	g.Phases = config.Phases
+	g.BuildFuncs = config.BuildFuncs
+	g.Container = config.Container
+	g.MaxProviders = config.MaxProviders
+	g.FailOnUnused = config.FailOnUnused
+	g.MaxDepth = config.MaxDepth
+
+	// Without a module the parser fails anyway, so the internal/ check is
+	// only skipped here.
+	if importPath, err := helpers.OutputImportPath(config.OutputPath()); err == nil {
+		g.OutputImportPath = importPath
+	}
+	return g
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/spf13/cobra"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs [directory]",
+	Short: "Write a Markdown overview of the dependency graph",
+	Long: `The 'docs' command scans the given directory and writes a Markdown
+page listing every provider, its dependencies and a Mermaid graph of the
+wiring. The page is deterministic, so it can be committed with the code.
+
+Example:
+  dix docs . --output DI.md`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		mt, err := parse(newParser(config), config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		if err := helpers.WriteTextFile(generator.GenerateDocs(mt), docsOutput); err != nil {
+			fatalDixError(err)
+		}
+		fmt.Printf("\033[32m[Docs]\033[0m Written to %s\n", docsOutput)
+	},
+}
+
+var docsOutput string
+
+func init() {
+	docsCmd.Flags().StringVarP(&docsOutput, "output", "o", "DI.md", "path of the Markdown file")
+	rootCmd.AddCommand(docsCmd)
+}
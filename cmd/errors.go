@@ -14,18 +14,29 @@ func fatalDixError(err error) {
 		return
 	}
 
+	printDixError(err)
+	os.Exit(1)
+}
+
+func printDixError(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			printDixError(e)
+		}
+		return
+	}
+
 	var parseErr *parser.ParseError
 	if errors.As(err, &parseErr) {
 		fmt.Fprintf(os.Stderr, "\033[31m[Error]\033[0m %s\n", parseErr.Error())
-		os.Exit(1)
+		return
 	}
 
 	var genErr *generator.GenerateError
 	if errors.As(err, &genErr) {
 		fmt.Fprintf(os.Stderr, "\033[31m[Error]\033[0m %s\n", genErr.Error())
-		os.Exit(1)
+		return
 	}
 
 	fmt.Fprintf(os.Stderr, "\033[31m[Error]\033[0m unknown: %v\n", err)
-	os.Exit(1)
 }
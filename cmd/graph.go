@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/smtdfc/dix/parser"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [directory]",
+	Short: "Print the dependency graph without generating code",
+	Long: `The 'graph' command scans the given directory and prints the graph of
+providers and their dependencies in DOT, Mermaid, JSON or CSV format.
+Nothing is generated.
+
+Example:
+  dix graph . --format dot | dot -Tsvg > graph.svg
+  dix graph ./internal --format mermaid --output graph.mmd`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		// Scan progress goes to standard error, since standard output must
+		// only hold the graph so that it can be piped.
+		p := newParser(config)
+		p.Logger = &parser.ConsoleLogger{Out: os.Stderr, Verbose: verbose}
+		mt, err := parse(p, config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		var out string
+		switch graphFormat {
+		case "dot":
+			out = generator.ExportDOT(mt)
+		case "mermaid":
+			out = generator.ExportMermaid(mt)
+		case "json":
+			out, err = generator.ExportJSON(mt)
+			if err != nil {
+				fatalDixError(err)
+			}
+		case "csv":
+			out = generator.ExportCSV(mt)
+		default:
+			fmt.Fprintf(os.Stderr, "\033[31m[Error]\033[0m unknown format %q, expected dot, mermaid, json or csv\n", graphFormat)
+			os.Exit(1)
+		}
+
+		if graphOutput == "" {
+			fmt.Print(out)
+			return
+		}
+		if err := helpers.WriteTextFile(out, graphOutput); err != nil {
+			fatalDixError(err)
+		}
+	},
+}
+
+var graphFormat string
+var graphOutput string
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "output format: dot, mermaid, json or csv")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "write to this file instead of standard output")
+	rootCmd.AddCommand(graphCmd)
+}
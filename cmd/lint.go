@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [directory]",
+	Short: "Report providers that cannot be wired correctly",
+	Long: `The 'lint' command scans the given directory and reports providers
+whose parameters cannot all be wired, for example parameters without a
+name or without a matching @Injectable provider.
+
+Example:
+  dix lint ./internal/app`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		mt, err := parse(newParser(config), config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		diagnostics := generator.Lint(mt)
+		for _, d := range diagnostics {
+			fmt.Fprintf(os.Stderr, "\033[33m[Lint]\033[0m %s\n", d.String())
+		}
+
+		if len(diagnostics) > 0 {
+			os.Exit(1)
+		}
+
+		fmt.Println("\033[32m[Lint]\033[0m No problems found ")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [directory]",
+	Short: "List every provider found in a directory",
+	Long: `The 'list' command scans the given directory and prints every
+provider with the type it returns, sorted by package and function name.
+
+Example:
+  dix list ./internal/app`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		mt, err := parse(newParser(config), config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "PROVIDER\tTYPE\tNOTES")
+		for _, info := range generator.ListProviders(mt) {
+			notes := []string{}
+			if info.Root {
+				notes = append(notes, "@Root")
+			}
+			if info.Disable {
+				notes = append(notes, "@Disable")
+			}
+			if info.Condition != "" {
+				notes = append(notes, "@If "+info.Condition)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", info.ID, info.Type, strings.Join(notes, " "))
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
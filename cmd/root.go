@@ -30,6 +30,10 @@ func Execute() {
 	}
 }
 
-func init() {
+// verbose also prints skipped packages, files and directories while
+// scanning.
+var verbose bool
 
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "also print the packages, files and symlinked directories skipped while scanning")
 }
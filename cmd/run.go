@@ -6,9 +6,7 @@ import (
 	"os/exec"
 	"time"
 
-	"github.com/smtdfc/dix/generator"
 	"github.com/smtdfc/dix/helpers"
-	"github.com/smtdfc/dix/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -31,9 +29,9 @@ Example:
 		if len(args) > 0 {
 			targetDir = args[0]
 		}
-		p := parser.NewParser()
-		g := generator.NewGenerator()
-		mt, err := p.Parse(targetDir)
+		p := newParser(config)
+		g := newGenerator(config)
+		mt, err := parse(p, config, targetDir)
 		if err != nil {
 			fatalDixError(err)
 		}
@@ -49,11 +47,8 @@ Example:
 		if err != nil {
 			fatalDixError(err)
 		}
-		outputPath := "./generated/dix/root.go"
-		if config.Output != "" {
-			outputPath = config.Output
-		}
-		err = helpers.WriteTextFile(code, outputPath)
+		outputPath := config.OutputPath()
+		err = helpers.WriteGeneratedFile(code, outputPath, forceWrite)
 		if err != nil {
 			fatalDixError(err)
 		}
@@ -72,6 +67,7 @@ Example:
 }
 
 func init() {
+	runCmd.Flags().BoolVar(&forceWrite, "force", false, "overwrite the output file even if it was not generated by dix")
 	rootCmd.AddCommand(runCmd)
 
 }
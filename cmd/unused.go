@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/helpers"
+	"github.com/spf13/cobra"
+)
+
+var unusedCmd = &cobra.Command{
+	Use:   "unused [directory]",
+	Short: "Report providers that are never used",
+	Long: `The 'unused' command scans the given directory and prints every
+provider that neither the @Root provider nor any @Entry provider needs,
+with its file and line. It exits with a non-zero status when some are
+found, so it can be used in CI.
+
+Example:
+  dix unused ./internal/app`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		mt, err := parse(newParser(config), config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		unused := generator.UnusedProviders(mt)
+		for _, u := range unused {
+			fmt.Fprintf(os.Stderr, "\033[33m[Unused]\033[0m %s:%d: %s is never used\n", u.File, u.Line, generator.ProviderID(u))
+		}
+
+		if len(unused) > 0 {
+			os.Exit(1)
+		}
+
+		fmt.Println("\033[32m[Unused]\033[0m Every provider is used")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unusedCmd)
+}
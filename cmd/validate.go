@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/smtdfc/dix/helpers"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [directory]",
+	Short: "Check that the wiring can be generated, without writing it",
+	Long: `The 'validate' command scans the given directory with the settings of
+dix.config.json and runs every check of 'dix wire', such as missing
+providers, cycles and @Final dependencies, without generating or writing
+any file. It exits with status 1 when a check fails, which makes it usable
+as a fast CI gate.
+
+Example:
+  dix validate .`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := helpers.ReadConfig()
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		targetDir := "."
+		if len(args) > 0 {
+			targetDir = args[0]
+		}
+
+		mt, err := parse(newParser(config), config, targetDir)
+		if err != nil {
+			fatalDixError(err)
+		}
+
+		if err := newGenerator(config).Validate(mt); err != nil {
+			fatalDixError(err)
+		}
+
+		fmt.Println("\033[32m[Validate]\033[0m No problems found")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/smtdfc/dix/generator"
@@ -27,16 +28,22 @@ var wireCmd = &cobra.Command{
 			targetDir = args[0]
 		}
 
-		p := parser.NewParser()
-		g := generator.NewGenerator()
-		mt, err := p.Parse(targetDir)
-		if err != nil {
-			fatalDixError(err)
+		p := newParser(config)
+		g := newGenerator(config)
+		if showTimings {
+			p.Timings = &parser.Timings{}
+			g.Timings = &generator.Timings{}
+		}
+		if showTrace {
+			g.Trace = os.Stderr
 		}
 
-		now := time.Now().Unix()
-		fileName := fmt.Sprintf("scan_%d.dix", now)
-		err = helpers.SaveMetadata(mt, fileName)
+		var mt *parser.Metadata
+		if metadataPath != "" {
+			mt, err = helpers.LoadMetadata(metadataPath)
+		} else {
+			mt, err = parse(p, config, targetDir)
+		}
 		if err != nil {
 			fatalDixError(err)
 		}
@@ -46,19 +53,58 @@ var wireCmd = &cobra.Command{
 			fatalDixError(err)
 		}
 
-		outputPath := "./generated/dix/root.go"
-		if config.Output != "" {
-			outputPath = config.Output
+		outputPath := config.OutputPath()
+		if checkOnly {
+			diff, err := helpers.DiffGenerated(code, outputPath)
+			if err != nil {
+				fatalDixError(err)
+			}
+			if diff != "" {
+				fmt.Print(diff)
+				fmt.Fprintf(os.Stderr, "\033[31m[Check]\033[0m %s is out of date, run 'dix wire' and commit the result\n", outputPath)
+				os.Exit(1)
+			}
+			fmt.Printf("\033[32m[Check]\033[0m %s is up to date\n", outputPath)
+			return
+		}
+
+		if metadataPath == "" {
+			now := time.Now().Unix()
+			fileName := fmt.Sprintf("scan_%d.dix", now)
+			err = helpers.SaveMetadata(mt, fileName)
+			if err != nil {
+				fatalDixError(err)
+			}
 		}
-		err = helpers.WriteTextFile(code, outputPath)
+
+		err = helpers.WriteGeneratedFile(code, outputPath, forceWrite)
 		if err != nil {
 			fatalDixError(err)
 		}
 
+		if showTimings && metadataPath == "" {
+			fmt.Printf(
+				"\033[32m[Timings]\033[0m load %s, annotations %s, resolve %s, graph %s, sort %s, codegen %s\n",
+				p.Timings.Load, p.Timings.Annotations, p.Timings.Resolve,
+				g.Timings.Graph, g.Timings.Sort, g.Timings.Codegen,
+			)
+		}
+
 	},
 }
 
+var showTimings bool
+var showTrace bool
+var checkOnly bool
+var forceWrite bool
+var metadataPath string
+
 func init() {
+	wireCmd.Flags().BoolVar(&showTimings, "timings", false, "print how long each phase took")
+	wireCmd.Flags().BoolVar(&showTrace, "trace", false, "print the construction order, parameter and import decisions to standard error")
+	wireCmd.Flags().BoolVar(&checkOnly, "check", false, "exit with status 1 if the generated file is out of date, without writing anything")
+	wireCmd.Flags().BoolVar(&forceWrite, "force", false, "overwrite the output file even if it was not generated by dix")
+	wireCmd.Flags().StringVar(&metadataPath, "metadata", "", "generate from a saved scan_<timestamp>.dix file instead of scanning the sources")
 	rootCmd.AddCommand(wireCmd)
 
 }
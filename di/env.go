@@ -0,0 +1,59 @@
+package di
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvString returns the value of the environment variable name, or an error
+// when the variable is not set. Generated root functions call it for @Env
+// parameters and return the error, so a missing setting stops the
+// application at startup.
+func EnvString(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("dix: environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// EnvInt is EnvString followed by strconv.Atoi.
+func EnvInt(name string) (int, error) {
+	value, err := EnvString(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("dix: environment variable %s: %w", name, err)
+	}
+	return n, nil
+}
+
+// EnvBool is EnvString followed by strconv.ParseBool.
+func EnvBool(name string) (bool, error) {
+	value, err := EnvString(name)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("dix: environment variable %s: %w", name, err)
+	}
+	return b, nil
+}
+
+// EnvDuration is EnvString followed by time.ParseDuration.
+func EnvDuration(name string) (time.Duration, error) {
+	value, err := EnvString(name)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("dix: environment variable %s: %w", name, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,10 @@
+package di
+
+import "context"
+
+// HealthCheck is a bound @Health method of an instance built by Root,
+// named after the provider that built it.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
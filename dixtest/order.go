@@ -0,0 +1,57 @@
+package dixtest
+
+import (
+	"fmt"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/parser"
+)
+
+// Order returns the providers reachable from the @Root provider in the order
+// the generated code constructs them. Providers ready at the same time are
+// ordered by @Order, then by provider ID, so the result is the same on every
+// run.
+func Order(metadata *parser.Metadata) ([]*parser.Provider, error) {
+	if metadata.Root == nil {
+		return nil, fmt.Errorf("dixtest: cannot find @Root provider")
+	}
+
+	graph, err := generator.BuildGraph(metadata.Root, generator.NewProviderMap(metadata.Providers))
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.Sort()
+}
+
+// AssertOrderBefore returns an error unless provider a is constructed before
+// provider b. Providers are matched by function name or by
+// "<package path>.<function name>".
+func AssertOrderBefore(metadata *parser.Metadata, a, b string) error {
+	sorted, err := Order(metadata)
+	if err != nil {
+		return err
+	}
+
+	ia, ib := indexOf(sorted, a), indexOf(sorted, b)
+	if ia < 0 {
+		return fmt.Errorf("dixtest: provider %s is not part of the graph", a)
+	}
+	if ib < 0 {
+		return fmt.Errorf("dixtest: provider %s is not part of the graph", b)
+	}
+	if ia >= ib {
+		return fmt.Errorf("dixtest: expected %s to be constructed before %s, got positions %d and %d", a, b, ia, ib)
+	}
+
+	return nil
+}
+
+func indexOf(providers []*parser.Provider, name string) int {
+	for i, p := range providers {
+		if p.Name == name || generator.ProviderID(p) == name {
+			return i
+		}
+	}
+	return -1
+}
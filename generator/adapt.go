@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// adaptation tells how a provider's value is turned into the value a
+// parameter expects when they differ by one pointer level.
+type adaptation int
+
+const (
+	adaptNone adaptation = iota
+	adaptAddress
+	adaptDeref
+)
+
+func togglePointer(t *parser.TypeInfo) *parser.TypeInfo {
+	c := *t
+	c.IsPointer = !t.IsPointer
+	return &c
+}
+
+// Resolve returns the provider for dep. A provider of T also serves a *T
+// parameter and a provider of *T a T parameter, when no exact match exists.
+func (m ProviderMap) Resolve(dep *parser.Dependency) (*parser.Provider, bool) {
+	p, _, ok := m.resolve(dep)
+	return p, ok
+}
+
+func (m ProviderMap) resolve(dep *parser.Dependency) (*parser.Provider, adaptation, bool) {
+	exact, ok := m[depKey(dep)]
+	if ok && !exact.IsDisable {
+		return exact, adaptNone, true
+	}
+
+	p, found := m[toggledDepKey(dep)]
+	if !found || (ok && p.IsDisable) {
+		// A disabled exact match is still returned so that callers can
+		// report it rather than a missing provider.
+		return exact, adaptNone, ok
+	}
+	if dep.Type.IsPointer {
+		return p, adaptAddress, true
+	}
+	return p, adaptDeref, true
+}
+
+// adaptExpr applies a to expr. Taking the address of a call result is not
+// allowed in Go, so in that case the value is first stored in a variable
+// inside a function literal.
+func (g *Generator) adaptExpr(expr ast.Expr, a adaptation, t *parser.TypeInfo, scope *Scope) ast.Expr {
+	switch a {
+	case adaptDeref:
+		return &ast.StarExpr{X: expr}
+	case adaptAddress:
+		if _, ok := expr.(*ast.Ident); ok {
+			return &ast.UnaryExpr{Op: token.AND, X: expr}
+		}
+
+		value := ast.NewIdent("value")
+		return &ast.CallExpr{
+			Fun: &ast.FuncLit{
+				Type: &ast.FuncType{
+					Params: &ast.FieldList{},
+					Results: &ast.FieldList{
+						List: []*ast.Field{{Type: g.TypeToASTExpr(t, scope)}},
+					},
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.AssignStmt{Lhs: []ast.Expr{value}, Tok: token.DEFINE, Rhs: []ast.Expr{expr}},
+						&ast.ReturnStmt{Results: []ast.Expr{&ast.UnaryExpr{Op: token.AND, X: value}}},
+					},
+				},
+			},
+		}
+	}
+	return expr
+}
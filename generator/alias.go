@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"go/ast"
+)
+
+// generateRootAlias emits func RootAlias(params) T { return Root(params) },
+// so callers of an older root function name keep working after a rename.
+func (g *Generator) generateRootAlias(root *ast.FuncDecl) *ast.FuncDecl {
+	args := []ast.Expr{}
+	for _, field := range root.Type.Params.List {
+		for _, name := range field.Names {
+			args = append(args, ast.NewIdent(name.Name))
+		}
+	}
+
+	return &ast.FuncDecl{
+		Doc: &ast.CommentGroup{
+			List: []*ast.Comment{{Text: "// " + g.RootAlias + " calls " + root.Name.Name + "."}},
+		},
+		Name: ast.NewIdent(g.RootAlias),
+		Type: root.Type,
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{Results: []ast.Expr{&ast.CallExpr{Fun: ast.NewIdent(root.Name.Name), Args: args}}},
+			},
+		},
+	}
+}
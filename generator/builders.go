@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// builderProviders returns the providers that get a Build function when
+// BuildFuncs is set: every enabled provider except the root, sorted by
+// provider ID.
+func builderProviders(metadata *parser.Metadata) []*parser.Provider {
+	providers := []*parser.Provider{}
+	for _, p := range metadata.Providers {
+		if !p.IsDisable && p.Return != nil {
+			providers = append(providers, p)
+		}
+	}
+
+	sort.SliceStable(providers, func(i, j int) bool {
+		return ProviderID(providers[i]) < ProviderID(providers[j])
+	})
+	return providers
+}
+
+// claimBuilderName picks the name of the Build function of p: BuildRepo
+// for NewRepo, then BuildRepoRepo with the package name, then a numeric
+// suffix when that is taken too. An @Expose provider such as NewDB.Pool
+// becomes BuildDBPool.
+func claimBuilderName(p *parser.Provider, scope *Scope) string {
+	return claimProviderName(p, "Build", scope.claim)
+}
+
+// claimProviderName derives a name for p from its function name, without
+// the New prefix, following prefix. claim reports whether a candidate is
+// still free and reserves it.
+func claimProviderName(p *parser.Provider, prefix string, claim func(string) bool) string {
+	base := strings.TrimPrefix(p.Name, "New")
+	if base == "" || !unicode.IsUpper([]rune(base)[0]) {
+		base = p.Name
+	}
+	base = sanitizeIdent(strings.ReplaceAll(base, ".", ""))
+
+	name := prefix + base
+	if claim(name) {
+		return name
+	}
+
+	pkg := []rune(sanitizeIdent(p.PackageName))
+	pkg[0] = unicode.ToUpper(pkg[0])
+	base = prefix + string(pkg) + base
+	name = base
+	for n := 2; !claim(name); n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	return name
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+func sourceComment(provider *parser.Provider) string {
+	return fmt.Sprintf(
+		"// from %s: %s.%s (%s:%d)",
+		strings.Join(provider.Annotations, ", "),
+		provider.PackageName,
+		provider.Name,
+		filepath.Base(provider.File),
+		provider.Line,
+	)
+}
+
+// stmtComment is the source comment of the statement at Index in the body
+// of the function Func.
+type stmtComment struct {
+	Func    string
+	Index   int
+	Comment string
+}
+
+// locateSourceComments finds the statements of comments in the bodies of
+// the functions declared by decls, so that they can be found again once the
+// code is printed.
+func locateSourceComments(decls []ast.Decl, comments map[ast.Stmt]string) []stmtComment {
+	located := []stmtComment{}
+	for _, decl := range decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+		for i, stmt := range fn.Body.List {
+			if comment, ok := comments[stmt]; ok {
+				located = append(located, stmtComment{Func: fn.Name.Name, Index: i, Comment: comment})
+			}
+		}
+	}
+	return located
+}
+
+// addSourceComments appends each comment to the first line of the
+// statement it was located on, then reformats the code so the trailing
+// comments are aligned.
+func addSourceComments(code string, comments []stmtComment) (string, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "", code, 0)
+	if err != nil {
+		return "", NewGenerateError(ErrorCodeGeneration, "failed to parse generated code for source comments", "", "", err)
+	}
+
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+
+	lines := strings.Split(code, "\n")
+	for _, c := range comments {
+		fn := funcs[c.Func]
+		if fn == nil || fn.Body == nil || c.Index >= len(fn.Body.List) {
+			continue
+		}
+		line := fset.Position(fn.Body.List[c.Index].Pos()).Line - 1
+		lines[line] += " " + c.Comment
+	}
+
+	formatted, err := format.Source([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return "", NewGenerateError(ErrorCodeGeneration, "failed to format source comments", "", "", err)
+	}
+
+	return string(formatted), nil
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSourceComments(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+type Config struct{}
+
+// @Injectable
+func NewConfig() (*Config, error) { return &Config{}, nil }
+
+type Cache struct{}
+
+// @Injectable
+func NewCache() *Cache { return &Cache{} }
+
+// @Injectable
+// @If UseDisk
+func NewDiskCache() *Cache { return &Cache{} }
+
+func UseDisk() bool { return false }
+
+type DB struct{}
+
+// @Injectable
+// @Root
+func NewDB(cfg *Config, cache *Cache) *DB { return &DB{} }
+`,
+	}
+
+	g := NewGenerator()
+	g.SourceComments = true
+	code := generateFiles(t, g, files)
+
+	for _, line := range []string{
+		"Config0, err := store.NewConfig()",
+		"var Cache0 *store.Cache",
+		"DB0 := store.NewDB(Config0, Cache0)",
+	} {
+		found := false
+		for _, l := range strings.Split(code, "\n") {
+			if strings.Contains(l, line) {
+				found = true
+				if !strings.Contains(l, "// from @Injectable") {
+					t.Errorf("line %q has no source comment", l)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("generated code has no line %q:\n%s", line, code)
+		}
+	}
+	vetGenerated(t, files, code)
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// conditionalBranches returns the @If alternatives of provider followed by
+// provider itself, which is used when no predicate holds.
+func (g *Generator) conditionalBranches(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) ([]ast.Expr, []ast.Expr, error) {
+	conds := []ast.Expr{}
+	calls := []ast.Expr{}
+	for _, alt := range provider.Alternatives {
+		call, err := g.generateCall(alt, scope, providerMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		conds = append(conds, &ast.CallExpr{
+			Fun: scope.Qualify(alt.PackagePath, alt.Condition),
+		})
+		calls = append(calls, call)
+	}
+
+	fallback, err := g.generateCall(provider, scope, providerMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conds, append(calls, fallback), nil
+}
+
+// buildIfChain nests one if/else per condition; branch turns a provider
+// call into the statement that consumes it.
+func buildIfChain(conds []ast.Expr, calls []ast.Expr, branch func(ast.Expr) ast.Stmt) ast.Stmt {
+	var stmt ast.Stmt = &ast.BlockStmt{List: []ast.Stmt{branch(calls[len(calls)-1])}}
+	for i := len(conds) - 1; i >= 0; i-- {
+		stmt = &ast.IfStmt{
+			Cond: conds[i],
+			Body: &ast.BlockStmt{List: []ast.Stmt{branch(calls[i])}},
+			Else: stmt,
+		}
+	}
+	return stmt
+}
+
+func (g *Generator) GenerateConditionalStmts(ident *ast.Ident, provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) ([]ast.Stmt, error) {
+	conds, calls, err := g.conditionalBranches(provider, scope, providerMap)
+	if err != nil {
+		return nil, err
+	}
+
+	decl := &ast.DeclStmt{
+		Decl: &ast.GenDecl{
+			Tok: token.VAR,
+			Specs: []ast.Spec{
+				&ast.ValueSpec{
+					Names: []*ast.Ident{ident},
+					Type:  g.TypeToASTExpr(provider.Return.Type, scope),
+				},
+			},
+		},
+	}
+
+	ifStmt := buildIfChain(conds, calls, func(call ast.Expr) ast.Stmt {
+		return &ast.AssignStmt{
+			Lhs: []ast.Expr{ident},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{call},
+		}
+	})
+
+	return []ast.Stmt{decl, ifStmt}, nil
+}
+
+// GenerateConditionalExpr is used where the value is needed as an expression,
+// such as a singleton dependency, and wraps the if/else chain in a function
+// literal that is called immediately.
+func (g *Generator) GenerateConditionalExpr(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (ast.Expr, error) {
+	conds, calls, err := g.conditionalBranches(provider, scope, providerMap)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []ast.Stmt{}
+	for i, cond := range conds {
+		body = append(body, &ast.IfStmt{
+			Cond: cond,
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{calls[i]}}}},
+		})
+	}
+	body = append(body, &ast.ReturnStmt{Results: []ast.Expr{calls[len(calls)-1]}})
+
+	return &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: g.TypeToASTExpr(provider.Return.Type, scope)}},
+				},
+			},
+			Body: &ast.BlockStmt{List: body},
+		},
+	}, nil
+}
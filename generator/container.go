@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"unicode"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const (
+	containerTypeName = "Container"
+	containerFuncName = "BuildContainer"
+	containerRecvName = "c"
+)
+
+// containerField is an instance kept by the Container, read through the
+// getter method Getter.
+type containerField struct {
+	Getter   string
+	Field    string
+	Provider *parser.Provider
+	Value    *ast.Ident
+}
+
+// containerLit returns the &Container{...} literal holding every provider
+// built by BuildContainer and, when @Health is used, their health checks,
+// recording the fields for GenerateContainerDecls. Getters are named after
+// the provider function like Build functions are: Repo for NewRepo.
+func (g *Generator) containerLit(built []*parser.Provider, healthChecks []ast.Expr, scope *Scope) ast.Expr {
+	getters := make(map[string]bool)
+	if scope.UsesHealthChecks {
+		getters[healthFuncName] = true
+	}
+	claim := func(name string) bool {
+		if getters[name] {
+			return false
+		}
+		getters[name] = true
+		return true
+	}
+
+	scope.containerFields = nil
+	elts := []ast.Expr{}
+	for _, p := range built {
+		getter := []rune(claimProviderName(p, "", claim))
+		getter[0] = unicode.ToUpper(getter[0])
+		field := append([]rune{}, getter...)
+		field[0] = unicode.ToLower(field[0])
+
+		f := containerField{
+			Getter:   string(getter),
+			Field:    string(field),
+			Provider: p,
+			Value:    scope.Names[providerKey(p)],
+		}
+		if token.IsKeyword(f.Field) {
+			f.Field += "_"
+		}
+		scope.containerFields = append(scope.containerFields, f)
+		elts = append(elts, &ast.KeyValueExpr{Key: ast.NewIdent(f.Field), Value: f.Value})
+	}
+	if scope.UsesHealthChecks {
+		elts = append(elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent(healthVarName),
+			Value: &ast.CompositeLit{Type: &ast.ArrayType{Elt: healthCheckType(scope)}, Elts: healthChecks},
+		})
+	}
+
+	return &ast.UnaryExpr{
+		Op: token.AND,
+		X:  &ast.CompositeLit{Type: ast.NewIdent(containerTypeName), Elts: elts},
+	}
+}
+
+// GenerateContainerDecls declares the Container type filled by
+// BuildContainer, one getter method per instance it holds and, when @Health
+// is used, a HealthChecks method.
+func (g *Generator) GenerateContainerDecls(scope *Scope) []ast.Decl {
+	fields := &ast.FieldList{}
+	for _, f := range scope.containerFields {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(f.Field)},
+			Type:  g.TypeToASTExpr(f.Provider.Return.Type, scope),
+		})
+	}
+	if scope.UsesHealthChecks {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(healthVarName)},
+			Type:  &ast.ArrayType{Elt: healthCheckType(scope)},
+		})
+	}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Tok: token.TYPE,
+			Doc: &ast.CommentGroup{List: []*ast.Comment{
+				{Text: "// " + containerTypeName + " holds the instances built by " + containerFuncName + "."},
+			}},
+			Specs: []ast.Spec{&ast.TypeSpec{
+				Name: ast.NewIdent(containerTypeName),
+				Type: &ast.StructType{Fields: fields},
+			}},
+		},
+	}
+
+	for _, f := range scope.containerFields {
+		decls = append(decls, containerGetter(f.Getter, f.Field, g.TypeToASTExpr(f.Provider.Return.Type, scope)))
+	}
+	if scope.UsesHealthChecks {
+		decls = append(decls, containerGetter(healthFuncName, healthVarName, &ast.ArrayType{Elt: healthCheckType(scope)}))
+	}
+
+	return decls
+}
+
+// containerGetter returns the Container method getter returning field.
+func containerGetter(getter, field string, typ ast.Expr) ast.Decl {
+	return &ast.FuncDecl{
+		Recv: &ast.FieldList{List: []*ast.Field{{
+			Names: []*ast.Ident{ast.NewIdent(containerRecvName)},
+			Type:  &ast.StarExpr{X: ast.NewIdent(containerTypeName)},
+		}}},
+		Name: ast.NewIdent(getter),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: typ}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{
+				&ast.SelectorExpr{X: ast.NewIdent(containerRecvName), Sel: ast.NewIdent(field)},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestContainerCompiles generates a Container next to Root and runs code
+// reading every getter, expecting each to return the instance that was
+// wired into the others and BuildContainer to leave Root's package-level
+// state alone.
+func TestContainerCompiles(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+import "context"
+
+type DB struct{ Name string }
+
+// @Injectable
+// @Health Ping
+func NewDB() *DB { return &DB{Name: "container"} }
+
+func (db *DB) Ping(ctx context.Context) error { return nil }
+
+type Repo struct{ DB *DB }
+
+// @Injectable
+func NewRepo(db *DB) *Repo { return &Repo{DB: db} }
+
+type Service struct{ Repo *Repo }
+
+// @Injectable
+// @Root
+func NewService(r *Repo) *Service { return &Service{Repo: r} }
+`,
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+	"example.com/app/store"
+)
+
+func main() {
+	root := generated.Root()
+	root.Repo.DB.Name = "root"
+
+	c := generated.BuildContainer()
+	fmt.Println(c.Service().Repo == c.Repo(), c.Repo().DB == c.DB(), c.DB().Name)
+	fmt.Println(len(c.HealthChecks()), c.HealthChecks()[0].Name)
+
+	db, _ := generated.Get("example.com/app/store.NewDB")
+	fmt.Println(db.(*store.DB).Name, len(generated.HealthChecks()))
+}
+`
+
+	g := NewGenerator()
+	g.Container = true
+	g.Registry = true
+	code := generateFiles(t, g, files)
+	for _, getter := range []string{"func (c *Container) DB() *store.DB", "func (c *Container) Repo() *store.Repo", "func (c *Container) Service() *store.Service"} {
+		if !strings.Contains(code, getter) {
+			t.Errorf("missing getter %q:\n%s", getter, code)
+		}
+	}
+
+	got := runGenerated(t, files, code, main)
+	want := "true true container\n1 example.com/app/store.NewDB\nroot 1\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"go/ast"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// contextType is context.Context, passed by Root to the providers marked
+// @Context.
+var contextType = &parser.TypeInfo{Name: "Context", Pkg: "context"}
+
+const contextParamName = "ctx"
+
+// generateContextParam declares the ctx parameter of a root function if one
+// of the sorted providers is marked @Context, and returns nil otherwise.
+func (g *Generator) generateContextParam(sorted []*parser.Provider, scope *Scope) *ast.Field {
+	used := false
+	for _, p := range sorted {
+		deps := append([]*parser.Dependency{}, p.Deps...)
+		for _, alt := range p.Alternatives {
+			deps = append(deps, alt.Deps...)
+		}
+		for _, dep := range deps {
+			if dep.IsContext {
+				used = true
+			}
+		}
+	}
+	if !used {
+		return nil
+	}
+
+	ident := ast.NewIdent(contextParamName)
+	scope.claim(ident.Name)
+	scope.Names[contextType.Signature()] = ident
+
+	return &ast.Field{
+		Names: []*ast.Ident{ident},
+		Type:  g.TypeToASTExpr(contextType, scope),
+	}
+}
+
+// contextExpr is the ctx parameter of the root function being generated.
+func contextExpr(dep *parser.Dependency, scope *Scope) (ast.Expr, error) {
+	ident, ok := scope.Names[contextType.Signature()]
+	if !ok {
+		return nil, NewGenerateError(
+			ErrorCodeGeneration,
+			"@Context dependency used outside of a root function",
+			"",
+			dep.String(),
+			nil,
+		)
+	}
+	return ident, nil
+}
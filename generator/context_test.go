@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestContextMixedGraph wires @Context providers next to plain ones and
+// expects Root to take a ctx that reaches only the @Context providers.
+func TestContextMixedGraph(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+import "context"
+
+type key struct{}
+
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, key{}, name)
+}
+
+type Config struct{}
+
+// @Injectable
+func NewConfig() *Config { return &Config{} }
+
+type Client struct{ Name string }
+
+// @Injectable
+// @Context
+func NewClient(ctx context.Context, cfg *Config) (*Client, error) {
+	return &Client{Name: ctx.Value(key{}).(string)}, ctx.Err()
+}
+
+type Repo struct{ Client *Client }
+
+// @Injectable
+func NewRepo(c *Client) *Repo { return &Repo{Client: c} }
+
+type App struct {
+	Repo *Repo
+	Name string
+}
+
+// @Injectable
+// @Root
+// @Context
+func NewApp(ctx context.Context, r *Repo) *App {
+	return &App{Repo: r, Name: ctx.Value(key{}).(string)}
+}
+`,
+	}
+	main := `package main
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/app/generated"
+	"example.com/app/store"
+)
+
+func main() {
+	app, err := generated.Root(store.WithName(context.Background(), "startup"))
+	fmt.Println(app.Name, app.Repo.Client.Name, err)
+
+	ctx, cancel := context.WithCancel(store.WithName(context.Background(), "cancelled"))
+	cancel()
+	_, err = generated.Root(ctx)
+	fmt.Println(err)
+}
+`
+
+	code := generateFiles(t, NewGenerator(), files)
+	for _, line := range []string{
+		"func Root(ctx context.Context) (*store.App, error) {",
+		"store.NewConfig()",
+		"store.NewClient(ctx, Config0)",
+		"store.NewRepo(Client0)",
+		"store.NewApp(ctx, Repo0)",
+	} {
+		if !strings.Contains(code, line) {
+			t.Errorf("generated code has no %q:\n%s", line, code)
+		}
+	}
+
+	got := runGenerated(t, files, code, main)
+	want := "startup startup <nil>\ndix: provider example.com/app/store.NewClient: context canceled\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
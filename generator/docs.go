@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// GenerateDocs renders the scanned providers as a Markdown page with a
+// Mermaid graph of their dependencies and one section per provider. The
+// output only depends on the providers, not on the scan time or on absolute
+// paths, so it can be committed and diffed.
+func GenerateDocs(metadata *parser.Metadata) string {
+	var b strings.Builder
+
+	b.WriteString("# Dependency injection\n\n")
+	b.WriteString("<!-- Code generated by dix. DO NOT EDIT. -->\n\n")
+
+	b.WriteString("## Graph\n\n```mermaid\n")
+	b.WriteString(ExportMermaid(metadata))
+	b.WriteString("```\n\nArrows point from a provider to its dependencies. Dashed arrows are `di.Singleton` dependencies, built anew for each consumer, and labelled dotted arrows lead to the `@If` alternatives of a provider.\n")
+
+	providers := make(map[string]*parser.Provider)
+	var collect func(p *parser.Provider)
+	collect = func(p *parser.Provider) {
+		providers[ProviderID(p)] = p
+		for _, alt := range p.Alternatives {
+			collect(alt)
+		}
+	}
+	if metadata.Root != nil {
+		collect(metadata.Root)
+	}
+	for _, p := range metadata.Providers {
+		collect(p)
+	}
+
+	b.WriteString("\n## Providers\n")
+	for _, info := range ListProviders(metadata) {
+		p := providers[info.ID]
+
+		fmt.Fprintf(&b, "\n### `%s`\n\n", info.ID)
+		if info.Type != "" {
+			fmt.Fprintf(&b, "- Returns: `%s`\n", info.Type)
+		}
+		fmt.Fprintf(&b, "- Defined in: `%s:%d`\n", filepath.Base(p.File), p.Line)
+		if len(p.Annotations) > 0 {
+			fmt.Fprintf(&b, "- Annotations: `%s`\n", strings.Join(p.Annotations, "`, `"))
+		}
+
+		if len(p.Deps) == 0 {
+			b.WriteString("- Dependencies: none\n")
+			continue
+		}
+		b.WriteString("- Dependencies:\n")
+		for _, dep := range p.Deps {
+			fmt.Fprintf(&b, "  - `%s %s`: %s\n", dep.Name, typeString(dep.Type), dependencySource(dep))
+		}
+	}
+
+	return b.String()
+}
+
+// dependencySource describes where the value of dep comes from.
+func dependencySource(dep *parser.Dependency) string {
+	source := "shared instance"
+	switch {
+	case dep.Literal != "":
+		return fmt.Sprintf("literal `%s`", dep.Literal)
+	case dep.Env != "":
+		return fmt.Sprintf("environment variable `%s`", dep.Env)
+	case dep.IsContext:
+		return "the `ctx` parameter of Root"
+	case dep.Memoize:
+		source = "instance shared by `@Memoize` parameters (`di.Singleton`)"
+	case dep.IsSingleton:
+		source = "new instance (`di.Singleton`)"
+	case isLoggerDep(dep):
+		source = "shared instance, or the `logger` parameter of Root"
+	}
+	if dep.Optional {
+		source += ", or the zero value when nothing provides it (`@Optional`)"
+	}
+	return source
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// envKey indexes the variable holding an @Env value in Scope.Names. The
+// same variable read into two types is read twice.
+func envKey(dep *parser.Dependency) string {
+	return "env:" + dep.Env + "@" + dep.Type.Signature()
+}
+
+// envFunc is the di.Env function reading dep's type. The parser only
+// accepts string, int, bool and time.Duration.
+func envFunc(dep *parser.Dependency) string {
+	switch {
+	case dep.Type.Pkg == "time" && dep.Type.Name == "Duration":
+		return "EnvDuration"
+	case dep.Type.Name == "int":
+		return "EnvInt"
+	case dep.Type.Name == "bool":
+		return "EnvBool"
+	}
+	return "EnvString"
+}
+
+// generateEnvStmts reads every @Env variable used by the sorted providers,
+// including their @If alternatives, at the start of a root function, and
+// returns zero with the error when one is missing or malformed.
+func (g *Generator) generateEnvStmts(sorted []*parser.Provider, zero ast.Expr, scope *Scope) []ast.Stmt {
+	stmts := []ast.Stmt{}
+	for _, p := range sorted {
+		deps := append([]*parser.Dependency{}, p.Deps...)
+		for _, alt := range p.Alternatives {
+			deps = append(deps, alt.Deps...)
+		}
+
+		for _, dep := range deps {
+			if dep.Env == "" {
+				continue
+			}
+			if _, ok := scope.Names[envKey(dep)]; ok {
+				continue
+			}
+
+			id := scope.UniqueIdent(dep.Env)
+			scope.Names[envKey(dep)] = id
+			stmts = append(stmts,
+				&ast.AssignStmt{
+					Lhs: []ast.Expr{id, ast.NewIdent("err")},
+					Tok: token.DEFINE,
+					Rhs: []ast.Expr{&ast.CallExpr{
+						Fun: &ast.SelectorExpr{
+							X:   scope.Import("github.com/smtdfc/dix/di"),
+							Sel: ast.NewIdent(envFunc(dep)),
+						},
+						Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", dep.Env)}},
+					}},
+				},
+				&ast.IfStmt{
+					Cond: &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+					Body: &ast.BlockStmt{List: []ast.Stmt{
+						&ast.ReturnStmt{Results: []ast.Expr{zero, ast.NewIdent("err")}},
+					}},
+				},
+			)
+		}
+	}
+	return stmts
+}
+
+// envExpr is the variable read for an @Env parameter by generateEnvStmts.
+func envExpr(dep *parser.Dependency, scope *Scope) (ast.Expr, error) {
+	ident, ok := scope.Names[envKey(dep)]
+	if !ok {
+		return nil, NewGenerateError(
+			ErrorCodeGeneration,
+			"@Env dependency used outside of a root function",
+			"",
+			dep.String(),
+			nil,
+		)
+	}
+	return ident, nil
+}
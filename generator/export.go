@@ -0,0 +1,181 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+type Edge struct {
+	From      *parser.Provider
+	To        *parser.Provider
+	Singleton bool
+
+	// Alternative marks the edge from a fallback provider to one of its
+	// @If alternatives, which replaces it when its condition holds.
+	Alternative bool
+}
+
+func ProviderID(p *parser.Provider) string {
+	return p.PackagePath + "." + p.Name
+}
+
+// CollectEdges returns one edge per resolvable dependency of every parsed
+// provider and @If alternative, and one edge from each fallback provider to
+// its alternatives, sorted by provider ID. Several parameters resolved to
+// the same provider in the same way give a single edge. Unresolvable
+// dependencies are skipped; Lint reports them.
+func CollectEdges(metadata *parser.Metadata) []*Edge {
+	providers := append([]*parser.Provider{}, metadata.Providers...)
+	if metadata.Root != nil {
+		providers = append(providers, metadata.Root)
+	}
+
+	providerMap := NewProviderMap(metadata.Providers)
+
+	edges := []*Edge{}
+	seen := make(map[Edge]bool)
+	add := func(e Edge) {
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, &e)
+		}
+	}
+
+	var visit func(p *parser.Provider)
+	visit = func(p *parser.Provider) {
+		for _, dep := range p.Deps {
+			if dep.IsValue() {
+				continue
+			}
+			to, ok := providerMap.Resolve(dep)
+			if !ok {
+				continue
+			}
+			add(Edge{From: p, To: to, Singleton: dep.IsSingleton})
+		}
+		for _, alt := range p.Alternatives {
+			add(Edge{From: p, To: alt, Alternative: true})
+			visit(alt)
+		}
+	}
+	for _, p := range providers {
+		visit(p)
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		fi, fj := ProviderID(edges[i].From), ProviderID(edges[j].From)
+		if fi != fj {
+			return fi < fj
+		}
+		return ProviderID(edges[i].To) < ProviderID(edges[j].To)
+	})
+
+	return edges
+}
+
+func ExportCSV(metadata *parser.Metadata) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	_ = w.Write([]string{"from", "to", "singleton", "alternative"})
+	for _, e := range CollectEdges(metadata) {
+		_ = w.Write([]string{
+			ProviderID(e.From),
+			ProviderID(e.To),
+			strconv.FormatBool(e.Singleton),
+			strconv.FormatBool(e.Alternative),
+		})
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// ExportDOT renders the provider graph in Graphviz DOT format. Edges point
+// from a provider to its dependencies; di.Singleton edges are dashed, and
+// dotted edges labelled with the condition lead to @If alternatives.
+func ExportDOT(metadata *parser.Metadata) string {
+	var b strings.Builder
+
+	b.WriteString("digraph dix {\n")
+	for _, info := range ListProviders(metadata) {
+		fmt.Fprintf(&b, "  %q;\n", info.ID)
+	}
+	for _, e := range CollectEdges(metadata) {
+		style := ""
+		switch {
+		case e.Alternative:
+			style = fmt.Sprintf(" [style=dotted, label=%q]", "@If "+e.To.Condition)
+		case e.Singleton:
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "  %q -> %q%s;\n", ProviderID(e.From), ProviderID(e.To), style)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// ExportMermaid renders the provider graph as a Mermaid flowchart, with
+// nodes labelled package.Function. Edges point from a provider to its
+// dependencies; di.Singleton edges are dashed, and dotted edges labelled
+// with the condition lead to @If alternatives.
+func ExportMermaid(metadata *parser.Metadata) string {
+	var b strings.Builder
+
+	infos := ListProviders(metadata)
+	nodes := make(map[string]string, len(infos))
+	for i, info := range infos {
+		nodes[info.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	b.WriteString("graph TD\n")
+	for _, info := range infos {
+		fmt.Fprintf(&b, "  %s[\"%s.%s\"]\n", nodes[info.ID], filepath.Base(info.Package), info.Function)
+	}
+	for _, e := range CollectEdges(metadata) {
+		arrow := "-->"
+		switch {
+		case e.Alternative:
+			arrow = fmt.Sprintf("-.->|@If %s|", e.To.Condition)
+		case e.Singleton:
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", nodes[ProviderID(e.From)], arrow, nodes[ProviderID(e.To)])
+	}
+
+	return b.String()
+}
+
+type jsonEdge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Singleton   bool   `json:"singleton"`
+	Alternative bool   `json:"alternative,omitempty"`
+}
+
+// ExportJSON renders the providers, as listed by ListProviders, and the
+// edges between them as an indented JSON document.
+func ExportJSON(metadata *parser.Metadata) (string, error) {
+	edges := []jsonEdge{}
+	for _, e := range CollectEdges(metadata) {
+		edges = append(edges, jsonEdge{From: ProviderID(e.From), To: ProviderID(e.To), Singleton: e.Singleton, Alternative: e.Alternative})
+	}
+
+	body, err := json.MarshalIndent(struct {
+		Providers []*ProviderInfo `json:"providers"`
+		Edges     []jsonEdge      `json:"edges"`
+	}{ListProviders(metadata), edges}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(body) + "\n", nil
+}
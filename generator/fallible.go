@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// fallibleExprError reports a provider returning (T, error) that would have
+// to be built inside an expression, such as a di.Singleton argument or an
+// @If alternative, where the error cannot be returned.
+func fallibleExprError(provider *parser.Provider) error {
+	return NewGenerateError(
+		ErrorValidation,
+		"provider returns (T, error) and can only be injected as a regular dependency; mark it @SoftError to use it as a di.Singleton dependency or with @If",
+		provider.Name,
+		"",
+		nil,
+	)
+}
+
+// generateFallibleStmts builds provider, which returns (T, error), into id
+// and returns zero and the wrapped error from the root function when the
+// call fails. Decorators are applied once the error has been checked.
+func (g *Generator) generateFallibleStmts(id *ast.Ident, provider *parser.Provider, zero ast.Expr, scope *Scope, providerMap map[string]*parser.Provider) ([]ast.Stmt, error) {
+	call, err := g.providerCallExpr(provider, scope, providerMap)
+	if err != nil {
+		return nil, err
+	}
+
+	stmts := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{id, ast.NewIdent("err")},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{call},
+		},
+		&ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+			Body: &ast.BlockStmt{List: []ast.Stmt{
+				&ast.ReturnStmt{Results: []ast.Expr{
+					zero,
+					&ast.CallExpr{
+						Fun: &ast.SelectorExpr{X: scope.Import("fmt"), Sel: ast.NewIdent("Errorf")},
+						Args: []ast.Expr{
+							&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", "dix: provider "+ProviderID(provider)+": %w")},
+							ast.NewIdent("err"),
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	if decorated := g.GenerateDecorators(id, provider.Return.Type, scope); decorated != ast.Expr(id) {
+		stmts = append(stmts, &ast.AssignStmt{
+			Lhs: []ast.Expr{id},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{decorated},
+		})
+	}
+
+	return stmts, nil
+}
+
+// zeroExpr is the zero value of the root type t, written as typeExpr, that
+// a failing root function returns with its error.
+func zeroExpr(t *parser.TypeInfo, typeExpr ast.Expr) ast.Expr {
+	if t.IsPointer {
+		return ast.NewIdent("nil")
+	}
+	return &ast.StarExpr{X: &ast.CallExpr{Fun: ast.NewIdent("new"), Args: []ast.Expr{typeExpr}}}
+}
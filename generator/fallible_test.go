@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFallibleChain builds a chain where a fallible provider feeds another
+// fallible provider, which feeds plain ones, and expects Root to return the
+// first error without calling the providers after it.
+func TestFallibleChain(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+import "errors"
+
+var FailDB bool
+
+type Config struct{}
+
+// @Injectable
+func NewConfig() (*Config, error) { return &Config{}, nil }
+
+type DB struct{}
+
+// @Injectable
+func NewDB(cfg *Config) (*DB, error) {
+	if FailDB {
+		return nil, errors.New("db down")
+	}
+	return &DB{}, nil
+}
+
+type Repo struct{}
+
+var Repos int
+
+// @Injectable
+func NewRepo(db *DB) *Repo {
+	Repos++
+	return &Repo{}
+}
+
+type Service struct{}
+
+// @Injectable
+// @Root
+func NewService(r *Repo) *Service { return &Service{} }
+`,
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+	"example.com/app/store"
+)
+
+func main() {
+	s, err := generated.Root()
+	fmt.Println(s != nil, err, store.Repos)
+
+	store.FailDB = true
+	s, err = generated.Root()
+	fmt.Println(s != nil, err, store.Repos)
+}
+`
+
+	code := generateFiles(t, NewGenerator(), files)
+	if !strings.Contains(code, "func Root() (*store.Service, error) {") {
+		t.Fatalf("Root does not return an error:\n%s", code)
+	}
+
+	got := runGenerated(t, files, code, main)
+	want := "true <nil> 1\nfalse dix: provider example.com/app/store.NewDB: db down 1\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
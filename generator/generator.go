@@ -6,24 +6,110 @@ import (
 	"go/ast"
 	"go/format"
 	"go/token"
+	"io"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/smtdfc/dix/parser"
 )
 
-type Generator struct{}
+// Generator only holds configuration. Every call to Generate or
+// GenerateParts works on its own Scope, so one Generator may be shared by
+// concurrent generations as long as its fields are not modified meanwhile,
+// Timings is not set and Trace is nil, since generations writing to the
+// same Trace race. Use one Generator per generation to trace them.
+type Generator struct {
+	SourceComments bool
+	EmbedHash      bool
+	Format         *FormatConfig
+
+	// PackageName and RootName default to "generated" and "Root".
+	PackageName string
+	RootName    string
+
+	// RootAlias, when set, also generates a function of that name that
+	// calls the root function, to keep call sites working during a rename.
+	RootAlias string
+
+	// Registry makes Root also store every instance it builds in a map
+	// keyed by provider ID, readable through a generated Get function.
+	Registry bool
+
+	// HoistSingletons moves the construction of di.Singleton dependencies
+	// into generated helper functions, so a subtree requested in several
+	// places is written once. Only subtrees that need nothing from Root's
+	// body, such as other singletons or literals, are hoisted.
+	HoistSingletons bool
+
+	// BuildFuncs also generates a Build function per provider, such as
+	// BuildRepo for NewRepo, that builds only that provider and the ones it
+	// needs. They do not fill the registry.
+	BuildFuncs bool
+
+	// Container also generates a Container type holding every instance
+	// Root builds, with a getter method per instance, and a BuildContainer
+	// function that builds the same graph as Root and returns it.
+	Container bool
+
+	// Phases lists the @Phase names in construction order. Root calls the
+	// generated PhaseHook variable at the start of each phase.
+	Phases []string
+
+	// ImportAlias, when not nil, chooses the alias of each imported package
+	// from its import path. By default the alias is the last element of the
+	// path, such as repo for example.com/app/repo. An empty result keeps the
+	// default. Aliases that collide with another name in the generated file
+	// get a numeric suffix. When two imports want the same alias, the one
+	// with the smaller import path keeps it.
+	ImportAlias func(importPath string) string
+
+	// MaxProviders and MaxDepth make generation fail early on oversized
+	// input. Zero means no limit.
+	MaxProviders int
+	MaxDepth     int
+
+	// FailOnUnused makes generation fail when some enabled provider is not
+	// needed by the @Root provider or any @Entry provider, as reported by
+	// UnusedProviders.
+	FailOnUnused bool
+
+	// Trace, when not nil, receives a readable log of the decisions taken
+	// while generating: the construction order of each root function and
+	// why, how each parameter is passed and the alias of each import. It
+	// must not be shared between concurrent calls to Generate.
+	Trace io.Writer
+
+	// Timings, when not nil, receives the duration of each phase of
+	// Generate.
+	Timings *Timings
+
+	// OutputImportPath is the import path of the generated package. When
+	// set, imports that break Go's internal/ visibility rule are reported.
+	OutputImportPath string
+}
 
-const generatedBuildHeader = "//go:build !dix\n// +build !dix\n\n"
+const generatedBuildHeader = "// Code generated by dix. DO NOT EDIT.\n\n//go:build !dix\n// +build !dix\n\n"
 
 func NewGenerator() *Generator {
 	return &Generator{}
 }
 
+// GenerateImportStmt declares the imports of scope sorted by path, so the
+// declaration does not depend on map iteration order even before the file
+// is formatted.
 func (g *Generator) GenerateImportStmt(scope *Scope) (*ast.GenDecl, error) {
-	specs := []ast.Spec{}
+	scope.resolveImportAliases()
+	paths := make([]string, 0, len(scope.Imports))
+	for path := range scope.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
 
-	for path, ident := range scope.Imports {
+	specs := []ast.Spec{}
+	for _, path := range paths {
 		specs = append(specs, &ast.ImportSpec{
-			Name: ident,
+			Name: scope.Imports[path],
 			Path: &ast.BasicLit{
 				Value: fmt.Sprintf("%q", path),
 				Kind:  token.STRING,
@@ -43,6 +129,16 @@ func (g *Generator) GenerateDep(dep *parser.Dependency, scope *Scope) (ast.Expr,
 }
 
 func (g *Generator) GenerateDepWithMap(dep *parser.Dependency, scope *Scope, providerMap map[string]*parser.Provider) (ast.Expr, error) {
+	if dep.Literal != "" {
+		return literalExpr(dep.Literal)
+	}
+	if dep.Env != "" {
+		return envExpr(dep, scope)
+	}
+	if dep.IsContext {
+		return contextExpr(dep, scope)
+	}
+
 	if dep.IsSingleton {
 		if providerMap == nil {
 			return nil, NewGenerateError(
@@ -54,8 +150,23 @@ func (g *Generator) GenerateDepWithMap(dep *parser.Dependency, scope *Scope, pro
 			)
 		}
 
-		provider, ok := providerMap[dep.Type.Signature()]
+		provider, adapt, ok := ProviderMap(providerMap).resolve(dep)
+		if ProviderMap(providerMap).isAbsent(dep) {
+			return g.absentExpr(dep, scope), nil
+		}
 		if !ok {
+			// A Root parameter such as the logger has no provider to call
+			// again, so the shared value is wrapped instead.
+			if ident, ok := scope.Names[dep.Type.Signature()]; ok && isLoggerDep(dep) {
+				return &ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   scope.Import("github.com/smtdfc/dix/di"),
+						Sel: ast.NewIdent("NewSingleton"),
+					},
+					Args: []ast.Expr{ident},
+				}, nil
+			}
+
 			return nil, NewGenerateError(
 				ErrorDependencyResolve,
 				"singleton dependency provider not found",
@@ -65,10 +176,37 @@ func (g *Generator) GenerateDepWithMap(dep *parser.Dependency, scope *Scope, pro
 			)
 		}
 
-		providerCall, err := g.GenerateCallProviderWithMap(provider, scope, providerMap)
+		// Singleton dependencies are expanded inline here rather than taken
+		// from the sorted graph, so the disabled guard in BuildGraph must be
+		// repeated.
+		if provider.IsDisable {
+			return nil, NewGenerateError(
+				ErrorDependencyResolve,
+				"disabled provider cannot be used as singleton dependency",
+				provider.Name,
+				dep.Type.Signature(),
+				nil,
+			)
+		}
+
+		build := func() (ast.Expr, error) {
+			if g.HoistSingletons && isStandalone(provider, providerMap, make(map[*parser.Provider]bool)) {
+				return g.singletonHelper(provider, scope, providerMap)
+			}
+			return g.GenerateCallProviderWithMap(provider, scope, providerMap)
+		}
+
+		var providerCall ast.Expr
+		var err error
+		if dep.Memoize {
+			providerCall, err = g.memoizedSingleton(provider, scope, build)
+		} else {
+			providerCall, err = build()
+		}
 		if err != nil {
 			return nil, err
 		}
+		providerCall = g.adaptExpr(providerCall, adapt, dep.Type, scope)
 
 		diPkg := scope.Import("github.com/smtdfc/dix/di")
 		return &ast.CallExpr{
@@ -80,18 +218,28 @@ func (g *Generator) GenerateDepWithMap(dep *parser.Dependency, scope *Scope, pro
 		}, nil
 	}
 
-	ident, ok := scope.Names[dep.Type.Signature()]
+	if ident, ok := scope.Names[depKey(dep)]; ok {
+		return ident, nil
+	}
+
+	ident, ok := scope.Names[toggledDepKey(dep)]
+	if !ok && dep.Optional {
+		return g.absentExpr(dep, scope), nil
+	}
 	if !ok {
 		return nil, NewGenerateError(
 			ErrorDependencyResolve,
 			"dependency is not available in generated container scope",
 			"",
-			dep.Type.Signature(),
+			depKey(dep),
 			nil,
 		)
 	}
 
-	return ident, nil
+	if dep.Type.IsPointer {
+		return g.adaptExpr(ident, adaptAddress, dep.Type, scope), nil
+	}
+	return g.adaptExpr(ident, adaptDeref, dep.Type, scope), nil
 }
 
 func (g *Generator) GenerateCallProvider(provider *parser.Provider, scope *Scope) (ast.Expr, error) {
@@ -99,6 +247,53 @@ func (g *Generator) GenerateCallProvider(provider *parser.Provider, scope *Scope
 }
 
 func (g *Generator) GenerateCallProviderWithMap(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (ast.Expr, error) {
+	if len(provider.Alternatives) > 0 {
+		return g.GenerateConditionalExpr(provider, scope, providerMap)
+	}
+
+	return g.generateCall(provider, scope, providerMap)
+}
+
+func (g *Generator) generateCall(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (ast.Expr, error) {
+	var expr ast.Expr
+	if len(provider.FieldPath) > 0 {
+		fieldExpr, err := g.GenerateFieldSelector(provider, scope, providerMap)
+		if err != nil {
+			return nil, err
+		}
+		expr = fieldExpr
+	} else if provider.IsBinding {
+		source, err := g.GenerateDepWithMap(provider.Deps[0], scope, providerMap)
+		if err != nil {
+			return nil, err
+		}
+		// The conversion gives the instance its interface type, which a
+		// di.Singleton of the interface needs.
+		expr = &ast.CallExpr{
+			Fun:  g.TypeToASTExpr(provider.Return.Type, scope),
+			Args: []ast.Expr{source},
+		}
+	} else {
+		if provider.ReturnsError {
+			return nil, fallibleExprError(provider)
+		}
+
+		callExpr, err := g.providerCallExpr(provider, scope, providerMap)
+		if err != nil {
+			return nil, err
+		}
+		expr = callExpr
+		if provider.SoftError {
+			expr = g.softErrorExpr(provider, expr, scope)
+		}
+	}
+
+	return g.GenerateDecorators(expr, provider.Return.Type, scope), nil
+}
+
+// providerCallExpr calls the function of provider with its dependencies,
+// without handling a returned error or applying decorators.
+func (g *Generator) providerCallExpr(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (*ast.CallExpr, error) {
 	args := []ast.Expr{}
 
 	for _, dep := range provider.Deps {
@@ -107,18 +302,67 @@ func (g *Generator) GenerateCallProviderWithMap(provider *parser.Provider, scope
 			return nil, err
 		}
 		args = append(args, argExpr)
+		g.traceDep(provider, dep, providerMap)
+	}
+
+	if provider.PackageName == "main" && provider.PackagePath != scope.LocalPath {
+		return nil, NewGenerateError(
+			ErrorValidation,
+			"package main cannot be imported; generate into that package to wire its providers",
+			provider.Name,
+			"",
+			nil,
+		)
+	}
+
+	if provider.Method != "" {
+		return methodCallExpr(provider, args), nil
 	}
 
-	pkgAlias := scope.Import(provider.PackagePath)
 	return &ast.CallExpr{
-		Fun: &ast.SelectorExpr{
-			X:   pkgAlias,
-			Sel: ast.NewIdent(provider.Name),
-		},
+		Fun:  scope.Qualify(provider.PackagePath, provider.Name),
 		Args: args,
 	}, nil
 }
 
+// GenerateDecorators wraps expr in every @Decorator registered for t, in
+// declaration order, so the first declared decorator is applied first.
+func (g *Generator) GenerateDecorators(expr ast.Expr, t *parser.TypeInfo, scope *Scope) ast.Expr {
+	for _, d := range scope.Decorators[t.Signature()] {
+		expr = &ast.CallExpr{
+			Fun:  scope.Qualify(d.PackagePath, d.Name),
+			Args: []ast.Expr{expr},
+		}
+	}
+	return expr
+}
+
+func (g *Generator) GenerateFieldSelector(provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (ast.Expr, error) {
+	if len(provider.Deps) != 1 {
+		return nil, NewGenerateError(
+			ErrorCodeGeneration,
+			"exposed field provider must depend on exactly one source",
+			provider.Name,
+			"",
+			nil,
+		)
+	}
+
+	expr, err := g.GenerateDepWithMap(provider.Deps[0], scope, providerMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range provider.FieldPath {
+		expr = &ast.SelectorExpr{
+			X:   expr,
+			Sel: ast.NewIdent(field),
+		}
+	}
+
+	return expr, nil
+}
+
 func (g *Generator) GenerateCreateObjectStmt(ident *ast.Ident, provider *parser.Provider, scope *Scope, providerMap map[string]*parser.Provider) (ast.Stmt, error) {
 	callExpr, err := g.GenerateCallProviderWithMap(provider, scope, providerMap)
 	if err != nil {
@@ -132,114 +376,435 @@ func (g *Generator) GenerateCreateObjectStmt(ident *ast.Ident, provider *parser.
 	}, nil
 }
 
-func (g *Generator) Generate(metadata *parser.Metadata) (string, error) {
-	if metadata.Root == nil {
-		return "", NewGenerateError(ErrorValidation, "cannot find @Root provider", "", "", nil)
+// Parts is the generated wiring before it is rendered to a file, for tools
+// that merge it into a larger generated file with their own import block.
+type Parts struct {
+	// Root is nil when the scan has only @Entry providers.
+	Root     *ast.FuncDecl
+	Alias    *ast.FuncDecl
+	Entries  []*ast.FuncDecl
+	Builders []*ast.FuncDecl
+	Scope    *Scope
+
+	// Comments holds the source comment of the statement creating each
+	// provider, see Generator.SourceComments.
+	Comments map[ast.Stmt]string
+
+	// Container holds the Container type, its getters and BuildContainer
+	// when Generator.Container is set.
+	Container []ast.Decl
+}
+
+// Imports returns the import path to alias mapping used by Root.
+func (p *Parts) Imports() map[string]string {
+	p.Scope.resolveImportAliases()
+	imports := make(map[string]string, len(p.Scope.Imports))
+	for path, ident := range p.Scope.Imports {
+		imports[path] = ident.Name
 	}
+	return imports
+}
 
-	scope := NewScope()
-	providerMap := make(map[string]*parser.Provider)
-	for _, c := range metadata.Providers {
-		providerMap[c.Return.Type.Signature()] = c
+func (g *Generator) GenerateParts(metadata *parser.Metadata) (*Parts, error) {
+	entries := entryProviders(metadata)
+	if metadata.Root == nil && len(entries) == 0 {
+		return nil, NewGenerateError(ErrorValidation, "cannot find @Root provider", "", "", nil)
 	}
 
-	graph, err := BuildGraph(metadata.Root, providerMap)
-	if err != nil {
-		return "", err
+	if err := g.validateNames(); err != nil {
+		return nil, err
+	}
+	if err := g.checkProviderLimit(metadata); err != nil {
+		return nil, err
+	}
+	if err := g.checkUnused(metadata); err != nil {
+		return nil, err
 	}
+	g.recordPhase(func(t *Timings) { *t = Timings{} })
 
-	// Track provider return types required as regular (non-singleton) dependencies.
-	nonSingletonTypes := make(map[string]bool)
-	for _, provider := range metadata.Providers {
-		for _, dep := range provider.Deps {
-			if !dep.IsSingleton {
-				nonSingletonTypes[dep.Type.Signature()] = true
-			}
+	scope := NewScope()
+	scope.ImportAlias = g.ImportAlias
+	scope.LocalPath = g.OutputImportPath
+	scope.claim(g.rootName())
+	for _, e := range entries {
+		scope.claim(g.rootName() + e.Entry)
+	}
+	if g.Registry {
+		scope.claim(registryVarName)
+		scope.claim(registryFuncName)
+	}
+	if hasHealthChecks(metadata) {
+		scope.UsesHealthChecks = true
+		scope.claim(healthVarName)
+		scope.claim(healthFuncName)
+	}
+	if g.RootAlias != "" && (metadata.Root == nil || !scope.claim(g.RootAlias)) {
+		return nil, NewGenerateError(
+			ErrorValidation,
+			fmt.Sprintf("root alias %q needs a @Root provider and a name not used elsewhere in the generated file", g.RootAlias),
+			"",
+			"",
+			nil,
+		)
+	}
+	if g.Container && (metadata.Root == nil || !scope.claim(containerTypeName) || !scope.claim(containerFuncName)) {
+		return nil, NewGenerateError(
+			ErrorValidation,
+			fmt.Sprintf("container generation needs a @Root provider and the names %s and %s not used elsewhere in the generated file", containerTypeName, containerFuncName),
+			"",
+			"",
+			nil,
+		)
+	}
+	builders := []*parser.Provider{}
+	builderNames := []string{}
+	if g.BuildFuncs {
+		builders = builderProviders(metadata)
+		for _, p := range builders {
+			builderNames = append(builderNames, claimBuilderName(p, scope))
 		}
 	}
+	providerMap := NewProviderMap(metadata.Providers)
+	for _, d := range metadata.Decorators {
+		sig := d.Return.Type.Signature()
+		scope.Decorators[sig] = append(scope.Decorators[sig], d)
+	}
+
+	parts := &Parts{
+		Scope:    scope,
+		Comments: make(map[ast.Stmt]string),
+	}
+
 	if metadata.Root != nil {
-		for _, dep := range metadata.Root.Deps {
-			if !dep.IsSingleton {
-				nonSingletonTypes[dep.Type.Signature()] = true
+		fn, err := g.generateRootFunc(g.rootName(), metadata.Root, scope, providerMap, parts.Comments, rootFunc)
+		if err != nil {
+			return nil, err
+		}
+		parts.Root = fn
+		if g.RootAlias != "" {
+			parts.Alias = g.generateRootAlias(fn)
+		}
+	}
+
+	if g.Container {
+		fn, err := g.generateRootFunc(containerFuncName, metadata.Root, scope, providerMap, parts.Comments, containerFunc)
+		if err != nil {
+			return nil, err
+		}
+		decls := g.GenerateContainerDecls(scope)
+		parts.Container = append([]ast.Decl{decls[0], fn}, decls[1:]...)
+	}
+
+	for _, e := range entries {
+		fn, err := g.generateRootFunc(g.rootName()+e.Entry, e, scope, providerMap, parts.Comments, rootFunc)
+		if err != nil {
+			return nil, err
+		}
+		parts.Entries = append(parts.Entries, fn)
+	}
+
+	for i, p := range builders {
+		fn, err := g.generateRootFunc(builderNames[i], p, scope, providerMap, parts.Comments, builderFunc)
+		if err != nil {
+			return nil, err
+		}
+		parts.Builders = append(parts.Builders, fn)
+	}
+
+	return parts, nil
+}
+
+type rootFuncKind int
+
+const (
+	// rootFunc returns root and fills the registry, when enabled, and the
+	// health check list.
+	rootFunc rootFuncKind = iota
+	// builderFunc returns root without filling anything.
+	builderFunc
+	// containerFunc returns a Container holding every instance it built and
+	// their health checks, without filling the registry or the package-level
+	// health check list.
+	containerFunc
+)
+
+// regularDepKeys returns the keys of the types that providers, including
+// their @If alternatives, take as regular rather than di.Singleton
+// dependencies.
+func regularDepKeys(providers []*parser.Provider) map[string]bool {
+	keys := make(map[string]bool)
+	for _, provider := range providers {
+		deps := append([]*parser.Dependency{}, provider.Deps...)
+		for _, alt := range provider.Alternatives {
+			deps = append(deps, alt.Deps...)
+		}
+		for _, dep := range deps {
+			if !dep.IsSingleton && !dep.IsValue() {
+				keys[depKey(dep)] = true
+				keys[toggledDepKey(dep)] = true
 			}
 		}
 	}
+	return keys
+}
+
+// generateRootFunc emits a function named name that builds root and every
+// provider it needs, in dependency order.
+func (g *Generator) generateRootFunc(name string, root *parser.Provider, scope *Scope, providerMap ProviderMap, comments map[ast.Stmt]string, kind rootFuncKind) (*ast.FuncDecl, error) {
+	scope.Names = make(map[string]*ast.Ident)
+	scope.memoized = nil
+	// Only Root writes package-level state; BuildContainer returns its
+	// health checks from the Container instead.
+	register := kind == rootFunc
+	collectHealth := kind != builderFunc
+
+	graphStart := time.Now()
+	graph, err := BuildGraph(root, providerMap)
+	if err != nil {
+		return nil, err
+	}
+	g.recordPhase(func(t *Timings) { t.Graph += time.Since(graphStart) })
+
+	if err := g.assignPhases(graph); err != nil {
+		return nil, err
+	}
 
+	sortStart := time.Now()
 	sorted, err := graph.Sort()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	g.recordPhase(func(t *Timings) { t.Sort += time.Since(sortStart) })
 
-	fset := token.NewFileSet()
-	stmts := []ast.Stmt{}
+	if err := g.checkDepthLimit(graph); err != nil {
+		return nil, err
+	}
+	g.traceOrder(name, graph, sorted)
 
+	params := &ast.FieldList{}
+	if ctx := g.generateContextParam(sorted, scope); ctx != nil {
+		params.List = append(params.List, ctx)
+	}
+	if logger := g.generateLoggerParam(sorted, providerMap, scope); logger != nil {
+		params.List = append(params.List, logger)
+	}
+
+	// Only providers this function's own graph needs as regular
+	// dependencies get a variable; the others are built inline for their
+	// di.Singleton parameters.
+	needed := regularDepKeys(sorted)
+
+	emitted := []*parser.Provider{}
+	fallible := false
 	for _, provider := range sorted {
-		isRoot := provider.Return.Type.Signature() == metadata.Root.Return.Type.Signature()
-		if !isRoot && !nonSingletonTypes[provider.Return.Type.Signature()] {
+		isRoot := providerKey(provider) == providerKey(root)
+		// Build functions do not collect health checks, so a @Health
+		// provider is only kept there when it is needed.
+		if !isRoot && !needed[providerKey(provider)] && (provider.Health == "" || !collectHealth) {
 			continue
 		}
+		emitted = append(emitted, provider)
+		fallible = fallible || provider.ReturnsError && len(provider.Alternatives) == 0
+	}
+
+	lastComp := sorted[len(sorted)-1]
+	resultType := g.TypeToASTExpr(lastComp.Return.Type, scope)
+	zero := zeroExpr(lastComp.Return.Type, resultType)
+	if kind == containerFunc {
+		resultType = &ast.StarExpr{X: ast.NewIdent(containerTypeName)}
+		zero = ast.NewIdent("nil")
+	}
+	built := []*parser.Provider{}
+
+	// @Env variables are read first, so that a missing one is reported
+	// before any provider runs.
+	stmts := g.generateEnvStmts(sorted, zero, scope)
+	fallible = fallible || len(stmts) > 0
+	phase := -1
+	healthChecks := []ast.Expr{}
+
+	for _, provider := range emitted {
+
+		if len(g.Phases) > 0 && graph.phases[provider] != phase {
+			phase = graph.phases[provider]
+			stmts = append(stmts, g.phaseHookStmt(phase, scope))
+		}
 
 		id := scope.UniqueIdent(provider.Return.Type.Name)
-		stmt, err := g.GenerateCreateObjectStmt(id, provider, scope, providerMap)
-		if err != nil {
-			return "", err
+		start := len(stmts)
+		if len(provider.Alternatives) > 0 {
+			created, err := g.GenerateConditionalStmts(id, provider, scope, providerMap)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, created...)
+		} else if provider.ReturnsError {
+			created, err := g.generateFallibleStmts(id, provider, zero, scope, providerMap)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, created...)
+		} else {
+			stmt, err := g.GenerateCreateObjectStmt(id, provider, scope, providerMap)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, stmt)
 		}
+		comments[stmts[start]] = sourceComment(provider)
+		stmts = slices.Insert(stmts, start, scope.takeMemoized()...)
+
+		scope.Names[providerKey(provider)] = id
+		built = append(built, provider)
 
-		scope.Names[provider.Return.Type.Signature()] = id
-		stmts = append(stmts, stmt)
+		if register && g.Registry {
+			stmts = append(stmts, registryAssignStmt(ProviderID(provider), id))
+		}
+		if provider.Health != "" {
+			healthChecks = append(healthChecks, healthCheckElt(provider, id))
+		}
 	}
 
-	lastComp := sorted[len(sorted)-1]
-	finalID, ok := scope.Names[lastComp.Return.Type.Signature()]
+	if register && scope.UsesHealthChecks {
+		stmts = append(stmts, healthAssignStmt(healthChecks, scope))
+	}
+
+	finalID, ok := scope.Names[providerKey(lastComp)]
 	if !ok {
-		return "", NewGenerateError(ErrorCodeGeneration, "failed to resolve root provider identifier", lastComp.Name, "", nil)
+		return nil, NewGenerateError(ErrorCodeGeneration, "failed to resolve root provider identifier", lastComp.Name, "", nil)
 	}
 
 	var finalExpr ast.Expr = finalID
+	if kind == containerFunc {
+		finalExpr = g.containerLit(built, healthChecks, scope)
+	}
 
-	stmts = append(stmts, &ast.ReturnStmt{
-		Results: []ast.Expr{finalExpr},
-	})
+	results := &ast.FieldList{
+		List: []*ast.Field{{Type: resultType}},
+	}
+	returnStmt := &ast.ReturnStmt{Results: []ast.Expr{finalExpr}}
+	if fallible {
+		results.List = append(results.List, &ast.Field{Type: ast.NewIdent("error")})
+		returnStmt.Results = append(returnStmt.Results, ast.NewIdent("nil"))
+	}
+	stmts = append(stmts, returnStmt)
 
-	fn := &ast.FuncDecl{
-		Name: ast.NewIdent("Root"),
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(name),
 		Type: &ast.FuncType{
-			Params: &ast.FieldList{},
-			Results: &ast.FieldList{
-				List: []*ast.Field{
-					{Type: g.TypeToASTExpr(lastComp.Return.Type, scope)},
-				},
-			},
+			Params:  params,
+			Results: results,
 		},
 		Body: &ast.BlockStmt{List: stmts},
+	}, nil
+}
+
+func (g *Generator) Generate(metadata *parser.Metadata) (string, error) {
+	start := time.Now()
+	parts, err := g.GenerateParts(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if err := g.checkInternalImports(parts.Scope); err != nil {
+		return "", err
+	}
+
+	implementsDecl := g.GenerateImplementsDecl(metadata, parts.Scope)
+
+	var softErrorDecl *ast.GenDecl
+	if parts.Scope.UsesSoftErrorHook {
+		softErrorDecl = g.GenerateSoftErrorHookDecl(parts.Scope)
 	}
 
-	importDecl, err := g.GenerateImportStmt(scope)
+	var phaseDecl *ast.GenDecl
+	if parts.Scope.UsesPhaseHook {
+		phaseDecl = g.GeneratePhaseHookDecl()
+	}
+
+	var healthDecls []ast.Decl
+	if parts.Scope.UsesHealthChecks {
+		healthDecls = g.GenerateHealthDecls(parts.Scope)
+	}
+
+	importDecl, err := g.GenerateImportStmt(parts.Scope)
 	if err != nil {
 		return "", err
 	}
+	g.traceImports(parts.Scope)
+
+	decls := []ast.Decl{}
+	if len(importDecl.Specs) > 0 {
+		decls = append(decls, importDecl)
+	}
+	if g.EmbedHash {
+		decls = append(decls, g.GenerateHashDecl(metadata))
+	}
+	if implementsDecl != nil {
+		decls = append(decls, implementsDecl)
+	}
+	if softErrorDecl != nil {
+		decls = append(decls, softErrorDecl)
+	}
+	if phaseDecl != nil {
+		decls = append(decls, phaseDecl)
+	}
+	if g.Registry {
+		decls = append(decls, g.GenerateRegistryDecls()...)
+	}
+	decls = append(decls, healthDecls...)
+	if parts.Root != nil {
+		decls = append(decls, parts.Root)
+	}
+	if parts.Alias != nil {
+		decls = append(decls, parts.Alias)
+	}
+	decls = append(decls, parts.Container...)
+	for _, fn := range parts.Entries {
+		decls = append(decls, fn)
+	}
+	for _, fn := range parts.Builders {
+		decls = append(decls, fn)
+	}
+	for _, fn := range parts.Scope.Helpers {
+		decls = append(decls, fn)
+	}
 
 	file := &ast.File{
-		Name:  ast.NewIdent("generated"),
-		Decls: []ast.Decl{importDecl, fn},
+		Name:  ast.NewIdent(g.packageName()),
+		Decls: decls,
 	}
 
+	fset := token.NewFileSet()
 	var buf bytes.Buffer
 	if err := format.Node(&buf, fset, file); err != nil {
 		return "", err
 	}
 
-	return generatedBuildHeader + buf.String(), nil
+	code := buf.String()
+	if g.SourceComments {
+		code, err = addSourceComments(code, locateSourceComments(decls, parts.Comments))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if g.Format != nil {
+		code, err = g.Format.Apply(code)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	g.recordPhase(func(t *Timings) { t.Codegen = time.Since(start) - t.Graph - t.Sort })
+
+	return generatedBuildHeader + code, nil
 }
 
 func (g *Generator) TypeToASTExpr(t *parser.TypeInfo, scope *Scope) ast.Expr {
 	var expr ast.Expr
 	if t.Pkg != "" {
-		alias := scope.Import(t.Pkg)
-		expr = &ast.SelectorExpr{
-			X:   alias,
-			Sel: ast.NewIdent(t.Name),
-		}
+		expr = scope.Qualify(t.Pkg, t.Name)
 	} else {
 		expr = ast.NewIdent(t.Name)
 	}
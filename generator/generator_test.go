@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+var concurrentFiles = map[string]string{
+	"repo/repo.go": `package repo
+
+type Config struct{}
+
+// @Injectable
+func NewConfig() (*Config, error) { return &Config{}, nil }
+
+type DB struct{}
+
+// @Injectable
+func NewDB(cfg *Config) *DB { return &DB{} }
+
+type Repo struct{ db *DB }
+
+// @Injectable
+func NewRepo(db *DB) *Repo { return &Repo{db: db} }
+`,
+	"app/app.go": `package app
+
+import (
+	"example.com/app/repo"
+	"github.com/smtdfc/dix/di"
+)
+
+type App struct{}
+
+// @Injectable
+// @Root
+func NewApp(r *repo.Repo, db di.Singleton[*repo.DB]) *App { return &App{} }
+`,
+}
+
+// TestGenerateConcurrent runs generations from many goroutines, sharing
+// one Generator and metadata or using one Generator each, and expects each
+// to give the output of a sequential run. Run it with -race.
+func TestGenerateConcurrent(t *testing.T) {
+	metadata := parseFiles(t, concurrentFiles)
+
+	configs := []func(g *Generator){
+		func(g *Generator) {},
+		func(g *Generator) { g.Registry = true },
+		func(g *Generator) { g.Container = true; g.BuildFuncs = true },
+		func(g *Generator) { g.HoistSingletons = true; g.SourceComments = true; g.EmbedHash = true },
+	}
+	newGenerator := func(i int) *Generator {
+		g := NewGenerator()
+		configs[i%len(configs)](g)
+		return g
+	}
+
+	want := make([]string, len(configs))
+	for i := range configs {
+		code, err := newGenerator(i).Generate(metadata)
+		if err != nil {
+			t.Fatalf("config %d: %v", i, err)
+		}
+		want[i] = code
+	}
+
+	shared := newGenerator(0)
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for i := range 32 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			code, err := shared.Generate(metadata)
+			if err != nil || code != want[0] {
+				errs <- "shared generator gave different output"
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			g := newGenerator(i)
+			var trace bytes.Buffer
+			g.Trace = &trace
+			code, err := g.Generate(metadata)
+			if err != nil || code != want[i%len(configs)] {
+				errs <- "independent generator gave different output"
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+// TestGenerateDeterministic generates the same scan many times, and from a
+// second scan, and expects byte-identical output each time.
+func TestGenerateDeterministic(t *testing.T) {
+	files := map[string]string{
+		"a/a.go":           "package a\n\ntype A struct{}\n\n// @Injectable\nfunc NewA() *A { return &A{} }\n",
+		"b/b.go":           "package b\n\ntype B struct{}\n\n// @Injectable\nfunc NewB() *B { return &B{} }\n",
+		"c/c.go":           "package c\n\ntype C struct{}\n\n// @Injectable\nfunc NewC() *C { return &C{} }\n",
+		"x/store/store.go": "package store\n\ntype D struct{}\n\n// @Injectable\nfunc NewD() *D { return &D{} }\n",
+		"y/store/store.go": "package store\n\ntype E struct{}\n\n// @Injectable\nfunc NewE() *E { return &E{} }\n",
+		"app/app.go": `package app
+
+import (
+	"example.com/app/a"
+	"example.com/app/b"
+	"example.com/app/c"
+	xstore "example.com/app/x/store"
+	ystore "example.com/app/y/store"
+)
+
+type App struct{}
+
+// @Injectable
+// @Root
+func NewApp(e *ystore.E, c *c.C, d *xstore.D, b *b.B, a *a.A) *App { return &App{} }
+`,
+	}
+
+	g := NewGenerator()
+	g.Registry = true
+	g.Container = true
+	g.BuildFuncs = true
+	g.EmbedHash = true
+	g.SourceComments = true
+
+	metadata := parseFiles(t, files)
+	want, err := g.Generate(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 50 {
+		got, err := g.Generate(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("run %d differs:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+
+	if got := generateFiles(t, g, files); got != want {
+		t.Errorf("second scan differs:\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,6 +1,11 @@
 package generator
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/smtdfc/dix/parser"
 )
 
@@ -11,12 +16,66 @@ type Node struct {
 
 type ProviderMap map[string]*parser.Provider
 
+// NewProviderMap indexes providers by return type. When several providers
+// return the same type, an enabled one wins over a disabled one, then a
+// @Primary one, otherwise the last one does.
+func NewProviderMap(providers []*parser.Provider) ProviderMap {
+	providerMap := make(ProviderMap)
+	for _, p := range providers {
+		if p.Return == nil {
+			continue
+		}
+		sig := providerKey(p)
+		if existing, ok := providerMap[sig]; ok {
+			if p.IsDisable && !existing.IsDisable {
+				continue
+			}
+			if existing.IsDisable == p.IsDisable && existing.IsPrimary && !p.IsPrimary {
+				continue
+			}
+		}
+		providerMap[sig] = p
+	}
+	return providerMap
+}
+
+// disabledDependencyError reports that dep of p is only provided by the
+// disabled provider disabled.
+func disabledDependencyError(p, disabled *parser.Provider, dep *parser.Dependency) error {
+	return NewGenerateError(
+		ErrorDependencyResolve,
+		fmt.Sprintf("the only provider for this dependency, %s, is marked @Disable", ProviderID(disabled)),
+		p.Name,
+		dep.String(),
+		nil,
+	)
+}
+
+// selfDependencyError reports that dep of p is provided by p itself, which
+// would otherwise surface as a circular dependency.
+func selfDependencyError(p *parser.Provider, dep *parser.Dependency) error {
+	return NewGenerateError(
+		ErrorDependencyResolve,
+		fmt.Sprintf("%s cannot depend on the type it provides; use @Decorator to wrap an existing instance", ProviderID(p)),
+		p.Name,
+		dep.String(),
+		nil,
+	)
+}
+
 type Graph struct {
 	Root *Node
+
+	// phases holds the phase index of each provider, see assignPhases.
+	phases map[*parser.Provider]int
 }
 
+// Sort orders providers so that every provider comes after its
+// dependencies. Among providers that are ready at the same time, the
+// earliest phase goes first, then the lowest @Order hint, then the provider
+// ID decides.
 func (g *Graph) Sort() ([]*parser.Provider, error) {
-	var sorted []*parser.Provider
+	var nodes []*Node
 	status := make(map[*Node]int)
 
 	var visit func(n *Node) error
@@ -43,7 +102,7 @@ func (g *Graph) Sort() ([]*parser.Provider, error) {
 		}
 
 		status[n] = 2
-		sorted = append(sorted, n.Provider)
+		nodes = append(nodes, n)
 		return nil
 	}
 
@@ -51,11 +110,60 @@ func (g *Graph) Sort() ([]*parser.Provider, error) {
 		return nil, err
 	}
 
+	pending := make(map[*Node]int)
+	dependents := make(map[*Node][]*Node)
+	for _, n := range nodes {
+		seen := make(map[*Node]bool)
+		for _, dep := range n.Deps {
+			if !seen[dep] {
+				seen[dep] = true
+				pending[n]++
+				dependents[dep] = append(dependents[dep], n)
+			}
+		}
+	}
+
+	ready := []*Node{}
+	for _, n := range nodes {
+		if pending[n] == 0 {
+			ready = append(ready, n)
+		}
+	}
+
+	sorted := make([]*parser.Provider, 0, len(nodes))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			a, b := ready[i].Provider, ready[j].Provider
+			if g.phases[a] != g.phases[b] {
+				return g.phases[a] < g.phases[b]
+			}
+			if a.Order != b.Order {
+				return a.Order < b.Order
+			}
+			return ProviderID(a) < ProviderID(b)
+		})
+
+		n := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, n.Provider)
+
+		for _, d := range dependents[n] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
 	return sorted, nil
 }
+
 func BuildGraph(root *parser.Provider, providerMap ProviderMap) (*Graph, error) {
 
 	visited := make(map[string]*Node)
+	// Missing providers are collected rather than returned so that every
+	// unresolved dependency in the graph is reported at once.
+	missing := []error{}
 
 	var buildNode func(p *parser.Provider) (*Node, error)
 	buildNode = func(p *parser.Provider) (*Node, error) {
@@ -69,7 +177,7 @@ func BuildGraph(root *parser.Provider, providerMap ProviderMap) (*Graph, error)
 			)
 		}
 
-		sig := p.Return.Type.Signature()
+		sig := providerKey(p)
 
 		if n, ok := visited[sig]; ok {
 			return n, nil
@@ -91,18 +199,56 @@ func BuildGraph(root *parser.Provider, providerMap ProviderMap) (*Graph, error)
 		}
 		visited[sig] = node
 
-		for _, dep := range p.Deps {
-			depSig := dep.Type.Signature()
+		deps := append([]*parser.Dependency{}, p.Deps...)
+		for _, alt := range p.Alternatives {
+			deps = append(deps, alt.Deps...)
+		}
+
+		for _, dep := range deps {
+			if dep.IsValue() {
+				continue
+			}
 
-			childProvider, ok := providerMap[depSig]
+			if providerMap.isAbsent(dep) {
+				continue
+			}
+			childProvider, ok := providerMap.Resolve(dep)
 			if !ok {
-				return nil, NewGenerateError(
+				if isLoggerDep(dep) {
+					continue
+				}
+				msg := "provider not found for dependency"
+				if qualifiers := providerMap.qualifiersOf(dep); dep.Qualifier == "" && len(qualifiers) > 0 {
+					msg = fmt.Sprintf("dependency is only provided with @Named %s; select one with @Use", strings.Join(qualifiers, ", "))
+				}
+				missing = append(missing, NewGenerateError(
 					ErrorDependencyResolve,
-					"provider not found for dependency",
+					msg,
 					p.Name,
 					dep.String(),
 					nil,
-				)
+				))
+				continue
+			}
+			if childProvider == p {
+				missing = append(missing, selfDependencyError(p, dep))
+				continue
+			}
+			if childProvider.IsDisable {
+				missing = append(missing, disabledDependencyError(p, childProvider, dep))
+				continue
+			}
+			// Fields exposed and interfaces bound by a @Final provider still
+			// read from it.
+			if childProvider.IsFinal && len(p.FieldPath) == 0 && !p.IsBinding {
+				missing = append(missing, NewGenerateError(
+					ErrorDependencyResolve,
+					fmt.Sprintf("%s is marked @Final and cannot be used as a dependency", ProviderID(childProvider)),
+					p.Name,
+					dep.String(),
+					nil,
+				))
+				continue
 			}
 
 			childNode, err := buildNode(childProvider)
@@ -125,5 +271,9 @@ func BuildGraph(root *parser.Provider, providerMap ProviderMap) (*Graph, error)
 		return nil, err
 	}
 
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+
 	return &Graph{Root: rootNode}, nil
 }
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelfDependency(t *testing.T) {
+	tests := []struct {
+		name string
+		repo string
+		want string
+	}{
+		{
+			name: "same type",
+			repo: "// @Injectable\nfunc NewRepo(r *Repo) *Repo { return r }\n",
+			want: "example.com/app/store.NewRepo cannot depend on the type it provides",
+		},
+		{
+			name: "other pointer level",
+			repo: "// @Injectable\nfunc NewRepo(r Repo) *Repo { return &r }\n",
+			want: "example.com/app/store.NewRepo cannot depend on the type it provides",
+		},
+		{
+			name: "cycle through another provider",
+			repo: "// @Injectable\nfunc NewRepo(c *Cache) *Repo { return &Repo{} }\n\ntype Cache struct{}\n\n// @Injectable\nfunc NewCache(r *Repo) *Cache { return &Cache{} }\n",
+			want: "circular dependency detected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := parseFiles(t, map[string]string{
+				"store/store.go": "package store\n\ntype Repo struct{}\n\n" + tt.repo + `
+type App struct{}
+
+// @Injectable
+// @Root
+func NewApp(r *Repo) *App { return &App{} }
+`,
+			})
+
+			_, err := NewGenerator().Generate(metadata)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const hashConstName = "dixGeneratedHash"
+
+// InputHash returns a digest of everything that affects the generated code:
+// providers, their dependencies, return types and annotations. File names
+// and line numbers are left out so moving code around does not change it.
+// Providers are hashed sorted by the type they provide, which does not
+// change the generated code, but keep their declaration order within a
+// type, which decides which of several unmarked providers is used, as do
+// decorators, which are applied in that order. @If alternatives are hashed
+// with their fallback in the order they are checked.
+func InputHash(metadata *parser.Metadata) string {
+	type entry struct {
+		key  string
+		line string
+	}
+	entries := []entry{}
+
+	var describe func(kind string, p *parser.Provider) string
+	describe = func(kind string, p *parser.Provider) string {
+		deps := []string{}
+		for _, d := range p.Deps {
+			deps = append(deps, fmt.Sprintf("%s:%s:%t:%s:%s:%t:%t:%t", d.Name, depKey(d), d.IsSingleton, d.Literal, d.Env, d.IsContext, d.Memoize, d.Optional))
+		}
+
+		ret := ""
+		if p.Return != nil {
+			ret = providerKey(p)
+		}
+
+		line := fmt.Sprintf(
+			"%s|%s|%s|%s|%t|%t|%s",
+			kind,
+			ProviderID(p),
+			ret,
+			strings.Join(deps, ","),
+			p.IsDisable,
+			p.ReturnsError,
+			strings.Join(p.Annotations, ","),
+		)
+		for _, alt := range p.Alternatives {
+			line += "\n\t" + describe("alt", alt)
+		}
+		return line
+	}
+	add := func(kind string, p *parser.Provider) {
+		key := kind
+		if p.Return != nil {
+			key += "|" + providerKey(p)
+		}
+		entries = append(entries, entry{key: key, line: describe(kind, p)})
+	}
+
+	if metadata.Root != nil {
+		add("root", metadata.Root)
+	}
+	for _, p := range metadata.Providers {
+		add("provider", p)
+	}
+	for _, d := range metadata.Decorators {
+		add("decorator", d)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.line
+	}
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (g *Generator) GenerateHashDecl(metadata *parser.Metadata) *ast.GenDecl {
+	return &ast.GenDecl{
+		Tok: token.CONST,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent(hashConstName)},
+				Values: []ast.Expr{
+					&ast.BasicLit{
+						Kind:  token.STRING,
+						Value: fmt.Sprintf("%q", InputHash(metadata)),
+					},
+				},
+			},
+		},
+	}
+}
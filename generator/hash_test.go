@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+func hashProvider(name, typeName string, alternatives ...*parser.Provider) *parser.Provider {
+	return &parser.Provider{
+		Name:         name,
+		PackagePath:  "example.com/app/store",
+		Return:       &parser.ReturnValue{Type: &parser.TypeInfo{Name: typeName, Pkg: "example.com/app/store", IsPointer: true}},
+		Alternatives: alternatives,
+	}
+}
+
+func TestInputHashOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    *parser.Metadata
+		changed bool
+	}{
+		{
+			name: "providers of different types",
+			a:    &parser.Metadata{Providers: []*parser.Provider{hashProvider("NewDB", "DB"), hashProvider("NewCache", "Cache")}},
+			b:    &parser.Metadata{Providers: []*parser.Provider{hashProvider("NewCache", "Cache"), hashProvider("NewDB", "DB")}},
+		},
+		{
+			name:    "duplicate providers",
+			a:       &parser.Metadata{Providers: []*parser.Provider{hashProvider("NewZCache", "Cache"), hashProvider("NewACache", "Cache")}},
+			b:       &parser.Metadata{Providers: []*parser.Provider{hashProvider("NewACache", "Cache"), hashProvider("NewZCache", "Cache")}},
+			changed: true,
+		},
+		{
+			name:    "decorators",
+			a:       &parser.Metadata{Decorators: []*parser.Provider{hashProvider("Trace", "DB"), hashProvider("Audit", "DB")}},
+			b:       &parser.Metadata{Decorators: []*parser.Provider{hashProvider("Audit", "DB"), hashProvider("Trace", "DB")}},
+			changed: true,
+		},
+		{
+			name: "alternatives",
+			a: &parser.Metadata{Providers: []*parser.Provider{
+				hashProvider("NewMemory", "Cache", hashProvider("NewRedis", "Cache"), hashProvider("NewDisk", "Cache")),
+			}},
+			b: &parser.Metadata{Providers: []*parser.Provider{
+				hashProvider("NewMemory", "Cache", hashProvider("NewDisk", "Cache"), hashProvider("NewRedis", "Cache")),
+			}},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := InputHash(tt.a) != InputHash(tt.b)
+			if changed != tt.changed {
+				t.Errorf("reordering changed the hash: %t, want %t", changed, tt.changed)
+			}
+		})
+	}
+}
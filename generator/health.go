@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const (
+	healthVarName  = "healthChecks"
+	healthFuncName = "HealthChecks"
+)
+
+// hasHealthChecks reports whether any provider declares @Health.
+func hasHealthChecks(metadata *parser.Metadata) bool {
+	if metadata.Root != nil && metadata.Root.Health != "" {
+		return true
+	}
+	for _, p := range metadata.Providers {
+		if p.Health != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// healthCheckElt binds the @Health method of the instance held by ident.
+func healthCheckElt(provider *parser.Provider, ident *ast.Ident) ast.Expr {
+	return &ast.CompositeLit{
+		Elts: []ast.Expr{
+			&ast.KeyValueExpr{
+				Key:   ast.NewIdent("Name"),
+				Value: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", ProviderID(provider))},
+			},
+			&ast.KeyValueExpr{
+				Key:   ast.NewIdent("Check"),
+				Value: &ast.SelectorExpr{X: ident, Sel: ast.NewIdent(provider.Health)},
+			},
+		},
+	}
+}
+
+func healthCheckType(scope *Scope) ast.Expr {
+	return &ast.SelectorExpr{X: scope.Import("github.com/smtdfc/dix/di"), Sel: ast.NewIdent("HealthCheck")}
+}
+
+// healthAssignStmt replaces the health checks with those of the instances
+// built by the current call of a root function.
+func healthAssignStmt(elts []ast.Expr, scope *Scope) ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{ast.NewIdent(healthVarName)},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{&ast.CompositeLit{Type: &ast.ArrayType{Elt: healthCheckType(scope)}, Elts: elts}},
+	}
+}
+
+// GenerateHealthDecls declares the slice filled by Root with the @Health
+// methods of the instances it builds and the HealthChecks function
+// returning it.
+func (g *Generator) GenerateHealthDecls(scope *Scope) []ast.Decl {
+	return []ast.Decl{
+		&ast.GenDecl{
+			Tok: token.VAR,
+			Specs: []ast.Spec{
+				&ast.ValueSpec{
+					Names: []*ast.Ident{ast.NewIdent(healthVarName)},
+					Type:  &ast.ArrayType{Elt: healthCheckType(scope)},
+				},
+			},
+		},
+		&ast.FuncDecl{
+			Name: ast.NewIdent(healthFuncName),
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: &ast.ArrayType{Elt: healthCheckType(scope)}}},
+				},
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent(healthVarName)}}},
+			},
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// testModulePath is the module path of the modules written by the tests.
+const testModulePath = "example.com/app"
+
+// testGoMod declares a module that may import github.com/smtdfc/dix/di,
+// resolved to this repository.
+func testGoMod(t *testing.T) (goMod, goSum []byte) {
+	t.Helper()
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err = os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod = []byte("module " + testModulePath + "\n\ngo 1.25.1\n\n" +
+		"require github.com/smtdfc/dix v0.0.0\n\n" +
+		"replace github.com/smtdfc/dix => " + filepath.ToSlash(root) + "\n")
+	return goMod, goSum
+}
+
+// parseFiles parses files, keyed by slash-separated path, as the module
+// example.com/app.
+func parseFiles(t *testing.T, files map[string]string) *parser.Metadata {
+	t.Helper()
+	goMod, goSum := testGoMod(t)
+	fsys := fstest.MapFS{
+		"go.mod": {Data: goMod},
+		"go.sum": {Data: goSum},
+	}
+	for name, src := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(src)}
+	}
+
+	p := parser.NewParser()
+	p.Logger = parser.NopLogger{}
+	metadata, err := p.ParseFS(fsys, ".")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return metadata
+}
+
+// generateFiles parses files and returns the code g generates from them.
+func generateFiles(t *testing.T, g *Generator, files map[string]string) string {
+	t.Helper()
+	code, err := g.Generate(parseFiles(t, files))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	return code
+}
+
+// writeGenerated writes files and code, as generated/root.go, to a new
+// module and returns its directory.
+func writeGenerated(t *testing.T, files map[string]string, code string) string {
+	t.Helper()
+	dir := t.TempDir()
+	goMod, goSum := testGoMod(t)
+	write := func(name string, data []byte) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", goMod)
+	write("go.sum", goSum)
+	for name, src := range files {
+		write(name, []byte(src))
+	}
+	write("generated/root.go", []byte(code))
+	return dir
+}
+
+// goCommand runs the go tool with args in dir and returns its output.
+func goCommand(t *testing.T, dir string, code string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s: %v\n%s\n%s", strings.Join(args, " "), err, out, code)
+	}
+	return string(out)
+}
+
+// vetGenerated runs go vet on the module written by writeGenerated.
+func vetGenerated(t *testing.T, files map[string]string, code string) {
+	t.Helper()
+	goCommand(t, writeGenerated(t, files, code), code, "vet", "./...")
+}
+
+// runGenerated writes main as cmd/main.go next to files and code, runs it
+// and returns its output. main is not part of the scanned files, since it
+// imports the generated package.
+func runGenerated(t *testing.T, files map[string]string, code, main string) string {
+	t.Helper()
+	dir := writeGenerated(t, files, code)
+	if err := os.MkdirAll(filepath.Join(dir, "cmd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return goCommand(t, dir, code, "run", "./cmd")
+}
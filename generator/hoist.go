@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"go/ast"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// isStandalone reports whether provider can be built without any value of
+// Root's body, that is when every dependency of it, including @If
+// alternatives, is a literal, an environment variable or itself a
+// standalone singleton.
+func isStandalone(provider *parser.Provider, providerMap ProviderMap, seen map[*parser.Provider]bool) bool {
+	if done, ok := seen[provider]; ok {
+		return done
+	}
+	seen[provider] = false
+
+	deps := append([]*parser.Dependency{}, provider.Deps...)
+	for _, alt := range provider.Alternatives {
+		deps = append(deps, alt.Deps...)
+	}
+
+	for _, dep := range deps {
+		if dep.IsContext || dep.Memoize || dep.Env != "" {
+			return false
+		}
+		if dep.IsValue() {
+			continue
+		}
+		if !dep.IsSingleton {
+			return false
+		}
+		p, ok := providerMap.Resolve(dep)
+		if !ok || !isStandalone(p, providerMap, seen) {
+			return false
+		}
+	}
+
+	seen[provider] = true
+	return true
+}
+
+// singletonHelper returns a call to a generated function that builds
+// provider, declaring the function the first time. Every call builds a new
+// instance, like the inline expansion it replaces.
+func (g *Generator) singletonHelper(provider *parser.Provider, scope *Scope, providerMap ProviderMap) (ast.Expr, error) {
+	id := ProviderID(provider)
+	ident, ok := scope.helperNames[id]
+	if !ok {
+		call, err := g.GenerateCallProviderWithMap(provider, scope, providerMap)
+		if err != nil {
+			return nil, err
+		}
+
+		ident = scope.UniqueIdent("new" + provider.Return.Type.Name)
+		if scope.helperNames == nil {
+			scope.helperNames = make(map[string]*ast.Ident)
+		}
+		scope.helperNames[id] = ident
+		scope.Helpers = append(scope.Helpers, &ast.FuncDecl{
+			Name: ident,
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: g.TypeToASTExpr(provider.Return.Type, scope)}},
+				},
+			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{call}}}},
+		})
+	}
+
+	return &ast.CallExpr{Fun: ident}, nil
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// ImpactOf returns the IDs of every provider that directly or transitively
+// depends on the provider with the given ID, sorted. Singleton dependencies
+// and @If alternatives count as dependencies too.
+func ImpactOf(metadata *parser.Metadata, id string) []string {
+	providerMap := NewProviderMap(metadata.Providers)
+
+	dependents := make(map[string][]string)
+	var link func(p *parser.Provider)
+	link = func(p *parser.Provider) {
+		for _, dep := range p.Deps {
+			if dep.IsValue() {
+				continue
+			}
+			if to, ok := providerMap.Resolve(dep); ok {
+				dependents[ProviderID(to)] = append(dependents[ProviderID(to)], ProviderID(p))
+			}
+		}
+		for _, alt := range p.Alternatives {
+			link(alt)
+			dependents[ProviderID(alt)] = append(dependents[ProviderID(alt)], ProviderID(p))
+		}
+	}
+
+	for _, p := range metadata.Providers {
+		link(p)
+	}
+	if metadata.Root != nil {
+		link(metadata.Root)
+	}
+
+	seen := map[string]bool{id: true}
+	impacted := []string{}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, d := range dependents[current] {
+			if !seen[d] {
+				seen[d] = true
+				impacted = append(impacted, d)
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	sort.Strings(impacted)
+	return impacted
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// GenerateImplementsDecl emits `var _ Iface = value` assertions for every
+// @Implements annotation, so a provider that stops satisfying its declared
+// interface breaks the build of the generated package as well. It returns
+// nil when there is nothing to assert.
+func (g *Generator) GenerateImplementsDecl(metadata *parser.Metadata, scope *Scope) *ast.GenDecl {
+	providers := []*parser.Provider{}
+	if metadata.Root != nil {
+		providers = append(providers, metadata.Root)
+	}
+	for _, p := range metadata.Providers {
+		providers = append(providers, p)
+		providers = append(providers, p.Alternatives...)
+	}
+
+	specs := []ast.Spec{}
+	for _, p := range providers {
+		for _, iface := range p.Implements {
+			specs = append(specs, &ast.ValueSpec{
+				Names:  []*ast.Ident{ast.NewIdent("_")},
+				Type:   g.TypeToASTExpr(iface, scope),
+				Values: []ast.Expr{g.zeroValueExpr(p.Return.Type, scope)},
+			})
+		}
+	}
+
+	if len(specs) == 0 {
+		return nil
+	}
+
+	decl := &ast.GenDecl{
+		Tok:   token.VAR,
+		Specs: specs,
+	}
+	if len(specs) > 1 {
+		decl.Lparen = token.Pos(1)
+	}
+	return decl
+}
+
+// zeroValueExpr returns (*T)(nil) for pointer types and *new(T) otherwise.
+func (g *Generator) zeroValueExpr(t *parser.TypeInfo, scope *Scope) ast.Expr {
+	if t.IsPointer {
+		return &ast.CallExpr{
+			Fun:  &ast.ParenExpr{X: g.TypeToASTExpr(t, scope)},
+			Args: []ast.Expr{ast.NewIdent("nil")},
+		}
+	}
+
+	return &ast.StarExpr{
+		X: &ast.CallExpr{
+			Fun:  ast.NewIdent("new"),
+			Args: []ast.Expr{g.TypeToASTExpr(t, scope)},
+		},
+	}
+}
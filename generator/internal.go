@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// findInternal returns the index of the last "internal" element of path,
+// which is the most restrictive one under Go's visibility rule.
+func findInternal(path string) (int, bool) {
+	switch {
+	case strings.HasSuffix(path, "/internal"):
+		return len(path) - len("internal"), true
+	case strings.Contains(path, "/internal/"):
+		return strings.LastIndex(path, "/internal/") + 1, true
+	case path == "internal", strings.HasPrefix(path, "internal/"):
+		return 0, true
+	}
+	return 0, false
+}
+
+// checkInternalImports reports every import of the generated package that
+// Go would reject because the output lies outside the import's internal/
+// boundary. It does nothing when OutputImportPath is not set.
+func (g *Generator) checkInternalImports(scope *Scope) error {
+	if g.OutputImportPath == "" {
+		return nil
+	}
+
+	paths := make([]string, 0, len(scope.Imports))
+	for path := range scope.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	errs := []error{}
+	for _, path := range paths {
+		i, ok := findInternal(path)
+		if !ok || i == 0 {
+			continue
+		}
+
+		parent := path[:i]
+		if strings.HasPrefix(g.OutputImportPath+"/", parent) {
+			continue
+		}
+
+		errs = append(errs, NewGenerateError(
+			ErrorValidation,
+			fmt.Sprintf("generated package %s cannot import internal package %s; place the output under %s", g.OutputImportPath, path, strings.TrimSuffix(parent, "/")),
+			"",
+			path,
+			nil,
+		))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// checkProviderLimit enforces MaxProviders before any graph is built.
+func (g *Generator) checkProviderLimit(metadata *parser.Metadata) error {
+	if g.MaxProviders <= 0 || len(metadata.Providers) <= g.MaxProviders {
+		return nil
+	}
+
+	return NewGenerateError(
+		ErrorValidation,
+		fmt.Sprintf("%d providers found, more than the limit of %d", len(metadata.Providers), g.MaxProviders),
+		"",
+		"",
+		nil,
+	)
+}
+
+// checkDepthLimit enforces MaxDepth on a sorted graph, where the depth of a
+// provider is the length of its longest dependency chain. Singleton
+// dependencies are expanded inline, so deep graphs can produce very large
+// expressions.
+func (g *Generator) checkDepthLimit(graph *Graph) error {
+	if g.MaxDepth <= 0 {
+		return nil
+	}
+
+	depths := make(map[*Node]int)
+	var depth func(n *Node) int
+	depth = func(n *Node) int {
+		if d, ok := depths[n]; ok {
+			return d
+		}
+		d := 1
+		for _, dep := range n.Deps {
+			d = max(d, depth(dep)+1)
+		}
+		depths[n] = d
+		return d
+	}
+
+	if d := depth(graph.Root); d > g.MaxDepth {
+		return NewGenerateError(
+			ErrorValidation,
+			fmt.Sprintf("dependency chain of depth %d exceeds the limit of %d", d, g.MaxDepth),
+			graph.Root.Provider.Name,
+			"",
+			nil,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+type Diagnostic struct {
+	File     string
+	Line     int
+	Provider string
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s [provider=%s]", d.File, d.Line, d.Message, d.Provider)
+}
+
+// Lint reports providers that would be generated with a wrong number of
+// arguments, without requiring a @Root or a successful graph build.
+func Lint(metadata *parser.Metadata) []*Diagnostic {
+	providers := append([]*parser.Provider{}, metadata.Providers...)
+	if metadata.Root != nil {
+		providers = append(providers, metadata.Root)
+	}
+
+	providerMap := NewProviderMap(metadata.Providers)
+
+	diagnostics := []*Diagnostic{}
+	for _, p := range providers {
+		if p.ParamCount >= 0 && p.ParamCount != len(p.Deps) {
+			diagnostics = append(diagnostics, &Diagnostic{
+				File:     p.File,
+				Line:     p.Line,
+				Provider: p.Name,
+				Message:  fmt.Sprintf("provider declares %d parameter(s) but only %d can be wired", p.ParamCount, len(p.Deps)),
+			})
+		}
+
+		for _, dep := range p.Deps {
+			if dep.IsValue() || providerMap.isAbsent(dep) {
+				continue
+			}
+			to, ok := providerMap.Resolve(dep)
+			if ok && to == p {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     p.File,
+					Line:     p.Line,
+					Provider: p.Name,
+					Message:  fmt.Sprintf("dependency%s is provided by %s itself", dep.String(), p.Name),
+				})
+				continue
+			}
+			if ok && to.IsDisable && !p.IsDisable {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     p.File,
+					Line:     p.Line,
+					Provider: p.Name,
+					Message:  fmt.Sprintf("dependency%s is only provided by %s, which is marked @Disable", dep.String(), ProviderID(to)),
+				})
+			}
+			if ok && to.IsFinal && len(p.FieldPath) == 0 && !p.IsBinding {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     p.File,
+					Line:     p.Line,
+					Provider: p.Name,
+					Message:  fmt.Sprintf("dependency%s is provided by %s, which is marked @Final", dep.String(), ProviderID(to)),
+				})
+			}
+			if !ok && !isLoggerDep(dep) {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     p.File,
+					Line:     p.Line,
+					Provider: p.Name,
+					Message:  fmt.Sprintf("no provider found for dependency%s", dep.String()),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].File != diagnostics[j].File {
+			return diagnostics[i].File < diagnostics[j].File
+		}
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+
+	return diagnostics
+}
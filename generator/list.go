@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+type ProviderInfo struct {
+	ID        string `json:"id"`
+	Package   string `json:"package"`
+	Function  string `json:"function"`
+	Type      string `json:"type"`
+	Root      bool   `json:"root,omitempty"`
+	Disable   bool   `json:"disable,omitempty"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// ListProviders returns one record per provider, including the root and
+// @If alternatives, sorted by provider ID.
+func ListProviders(metadata *parser.Metadata) []*ProviderInfo {
+	infos := []*ProviderInfo{}
+
+	var add func(p *parser.Provider, root bool)
+	add = func(p *parser.Provider, root bool) {
+		info := &ProviderInfo{
+			ID:        ProviderID(p),
+			Package:   p.PackagePath,
+			Function:  p.Name,
+			Root:      root,
+			Disable:   p.IsDisable,
+			Condition: p.Condition,
+		}
+		if p.Return != nil {
+			info.Type = typeString(p.Return.Type)
+		}
+		infos = append(infos, info)
+
+		for _, alt := range p.Alternatives {
+			add(alt, false)
+		}
+	}
+
+	if metadata.Root != nil {
+		add(metadata.Root, true)
+	}
+	for _, p := range metadata.Providers {
+		add(p, false)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+
+	return infos
+}
+
+// typeString renders t as Go would with full package paths, for example
+// *example.com/app/repo.Repo.
+func typeString(t *parser.TypeInfo) string {
+	s := t.Name
+	if t.Pkg != "" {
+		s = t.Pkg + "." + s
+	}
+	if t.IsPointer {
+		s = "*" + s
+	}
+	return s
+}
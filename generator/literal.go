@@ -0,0 +1,28 @@
+package generator
+
+import (
+	"go/ast"
+	goparser "go/parser"
+)
+
+// literalExpr rebuilds a @Value literal as a position-free AST node so it
+// prints cleanly inside the generated file.
+func literalExpr(literal string) (ast.Expr, error) {
+	expr, err := goparser.ParseExpr(literal)
+	if err != nil {
+		return nil, NewGenerateError(ErrorCodeGeneration, "invalid literal value", "", literal, err)
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return &ast.BasicLit{Kind: e.Kind, Value: e.Value}, nil
+	case *ast.Ident:
+		return ast.NewIdent(e.Name), nil
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.BasicLit); ok {
+			return &ast.UnaryExpr{Op: e.Op, X: &ast.BasicLit{Kind: lit.Kind, Value: lit.Value}}, nil
+		}
+	}
+
+	return nil, NewGenerateError(ErrorCodeGeneration, "unsupported literal value", "", literal, nil)
+}
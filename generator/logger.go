@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"go/ast"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// loggerType is *slog.Logger. When providers depend on it and no provider
+// returns it, the logger becomes a parameter of Root instead.
+var loggerType = &parser.TypeInfo{Name: "Logger", Pkg: "log/slog", IsPointer: true}
+
+const loggerParamName = "logger"
+
+func isLoggerDep(dep *parser.Dependency) bool {
+	return !dep.IsValue() && dep.Type.Signature() == loggerType.Signature()
+}
+
+// generateLoggerParam declares the Root parameter for *slog.Logger if one of
+// the sorted providers needs it and no provider returns it, and returns nil
+// otherwise.
+func (g *Generator) generateLoggerParam(sorted []*parser.Provider, providerMap map[string]*parser.Provider, scope *Scope) *ast.Field {
+	if _, ok := providerMap[loggerType.Signature()]; ok {
+		return nil
+	}
+
+	used := false
+	for _, p := range sorted {
+		deps := append([]*parser.Dependency{}, p.Deps...)
+		for _, alt := range p.Alternatives {
+			deps = append(deps, alt.Deps...)
+		}
+		for _, dep := range deps {
+			if isLoggerDep(dep) {
+				used = true
+			}
+		}
+	}
+	if !used {
+		return nil
+	}
+
+	// Generated variables always carry a numeric suffix, so the parameter
+	// name is free in every root function even if claimed before.
+	ident := ast.NewIdent(loggerParamName)
+	scope.claim(ident.Name)
+	scope.Names[loggerType.Signature()] = ident
+
+	return &ast.Field{
+		Names: []*ast.Ident{ident},
+		Type:  g.TypeToASTExpr(loggerType, scope),
+	}
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// memoizedSingleton returns the variable holding the instance shared by the
+// @Memoize parameters resolved to provider in the current root function.
+// The first call declares it with build, which creates the instance; the
+// declaration is queued until the root function emits the provider being
+// built, see takeMemoized.
+func (g *Generator) memoizedSingleton(provider *parser.Provider, scope *Scope, build func() (ast.Expr, error)) (*ast.Ident, error) {
+	if ident, ok := scope.memoized[providerKey(provider)]; ok {
+		return ident, nil
+	}
+
+	call, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	ident := scope.UniqueIdent(provider.Return.Type.Name)
+	if scope.memoized == nil {
+		scope.memoized = make(map[string]*ast.Ident)
+	}
+	scope.memoized[providerKey(provider)] = ident
+	scope.memoizedStmts = append(scope.memoizedStmts, &ast.AssignStmt{
+		Lhs: []ast.Expr{ident},
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{call},
+	})
+	return ident, nil
+}
+
+// takeMemoized returns and clears the declarations queued by
+// memoizedSingleton.
+func (s *Scope) takeMemoized() []ast.Stmt {
+	stmts := s.memoizedStmts
+	s.memoizedStmts = nil
+	return stmts
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMemoizeSharesInstance expects two @Memoize parameters to share one
+// construction, apart from the plain di.Singleton parameter and the
+// instance injected as *Pool.
+func TestMemoizeSharesInstance(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+import "github.com/smtdfc/dix/di"
+
+type Pool struct{ ID int }
+
+var Pools int
+
+// @Injectable
+func NewPool() *Pool {
+	Pools++
+	return &Pool{ID: Pools}
+}
+
+type Router struct{ Primary, Fallback, Other *Pool }
+
+// @Injectable
+// @Memoize primary
+// @Memoize fallback
+func NewRouter(primary, fallback, other di.Singleton[*Pool]) *Router {
+	return &Router{Primary: primary.Get(), Fallback: fallback.Get(), Other: other.Get()}
+}
+
+type App struct {
+	Router *Router
+	Pool   *Pool
+}
+
+// @Injectable
+// @Root
+func NewApp(r *Router, p *Pool) *App { return &App{Router: r, Pool: p} }
+`,
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+	"example.com/app/store"
+)
+
+func main() {
+	app := generated.Root()
+	r := app.Router
+	fmt.Println(r.Primary == r.Fallback, r.Primary == r.Other, r.Primary == app.Pool, store.Pools)
+}
+`
+
+	code := generateFiles(t, NewGenerator(), files)
+	if n := strings.Count(code, "store.NewPool()"); n != 3 {
+		t.Errorf("NewPool is called %d times, want 3:\n%s", n, code)
+	}
+
+	got := runGenerated(t, files, code, main)
+	if want := "true false false 3\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
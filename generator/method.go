@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// methodCallExpr calls the method provider on its receiver, the first of
+// args. Go takes the address of or dereferences a variable receiver as the
+// method needs, so a pointer adaptation of one is dropped; other adapted
+// receivers are parenthesized.
+func methodCallExpr(provider *parser.Provider, args []ast.Expr) *ast.CallExpr {
+	recv := args[0]
+	switch r := recv.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.CallExpr:
+	case *ast.UnaryExpr:
+		if ident, ok := r.X.(*ast.Ident); ok && r.Op == token.AND {
+			recv = ident
+		} else {
+			recv = &ast.ParenExpr{X: recv}
+		}
+	case *ast.StarExpr:
+		if ident, ok := r.X.(*ast.Ident); ok {
+			recv = ident
+		} else {
+			recv = &ast.ParenExpr{X: recv}
+		}
+	default:
+		recv = &ast.ParenExpr{X: recv}
+	}
+
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: recv, Sel: ast.NewIdent(provider.Method)},
+		Args: args[1:],
+	}
+}
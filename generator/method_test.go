@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMethodProvider calls method providers on the receiver built by the
+// provider of its type, shared with the other dependents of that type.
+func TestMethodProvider(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": `package store
+
+type Factory struct{ Prefix string }
+
+var Factories int
+
+// @Injectable
+func NewFactory() *Factory {
+	Factories++
+	return &Factory{Prefix: "conn"}
+}
+
+type Pool struct{ Size int }
+
+// @Injectable
+func NewPool() *Pool { return &Pool{Size: 4} }
+
+type Conn struct{ Name string }
+
+// @Injectable
+func (f *Factory) NewConn(pool *Pool) *Conn {
+	return &Conn{Name: f.Prefix + "-" + string(rune('0'+pool.Size))}
+}
+
+type Clock struct{ Zone string }
+
+// @Injectable
+func NewClock() Clock { return Clock{Zone: "UTC"} }
+
+type Now string
+
+// @Injectable
+func (Clock) Now() Now { return "now" }
+
+type App struct {
+	Conn    *Conn
+	Factory *Factory
+	Now     Now
+}
+
+// @Injectable
+// @Root
+func NewApp(c *Conn, f *Factory, now Now) *App { return &App{Conn: c, Factory: f, Now: now} }
+`,
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+	"example.com/app/store"
+)
+
+func main() {
+	app := generated.Root()
+	fmt.Println(app.Conn.Name, app.Factory.Prefix, app.Now, store.Factories)
+}
+`
+
+	code := generateFiles(t, NewGenerator(), files)
+	for _, call := range []string{".NewConn(Pool0)", ".Now()"} {
+		if !strings.Contains(code, call) {
+			t.Errorf("generated code has no %q:\n%s", call, code)
+		}
+	}
+
+	got := runGenerated(t, files, code, main)
+	if want := "conn-4 conn now 1\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const (
+	defaultPackageName = "generated"
+	defaultRootName    = "Root"
+)
+
+func (g *Generator) packageName() string {
+	if g.PackageName == "" {
+		return defaultPackageName
+	}
+	return g.PackageName
+}
+
+func (g *Generator) rootName() string {
+	if g.RootName == "" {
+		return defaultRootName
+	}
+	return g.RootName
+}
+
+// validateNames rejects package and function names that would make the
+// generated file fail to compile, such as paths, dashed names or keywords.
+func (g *Generator) validateNames() error {
+	names := []struct{ kind, value string }{
+		{"output package name", g.packageName()},
+		{"root function name", g.rootName()},
+	}
+	if g.RootAlias != "" {
+		names = append(names, struct{ kind, value string }{"root alias", g.RootAlias})
+	}
+
+	for _, n := range names {
+		if !token.IsIdentifier(n.value) || n.value == "_" {
+			return NewGenerateError(
+				ErrorValidation,
+				fmt.Sprintf("%s %q is not a valid Go identifier", n.kind, n.value),
+				"",
+				"",
+				nil,
+			)
+		}
+	}
+
+	return nil
+}
+
+// entryProviders returns the @Entry providers sorted by entry name.
+func entryProviders(metadata *parser.Metadata) []*parser.Provider {
+	entries := []*parser.Provider{}
+	if metadata.Root != nil && metadata.Root.Entry != "" {
+		entries = append(entries, metadata.Root)
+	}
+	for _, p := range metadata.Providers {
+		if p.Entry != "" {
+			entries = append(entries, p)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Entry < entries[j].Entry
+	})
+	return entries
+}
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"go/ast"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// isAbsent reports whether dep is an @Optional dependency that no enabled
+// provider satisfies, so that its zero value is passed instead.
+func (m ProviderMap) isAbsent(dep *parser.Dependency) bool {
+	if !dep.Optional {
+		return false
+	}
+	p, ok := m.Resolve(dep)
+	return !ok || p.IsDisable
+}
+
+// absentExpr is the zero value passed for an absent @Optional dependency:
+// nil for pointers, an empty di.Singleton for singleton dependencies and
+// *new(T) otherwise.
+func (g *Generator) absentExpr(dep *parser.Dependency, scope *Scope) ast.Expr {
+	typeExpr := g.TypeToASTExpr(dep.Type, scope)
+	if dep.IsSingleton {
+		return &ast.CompositeLit{Type: &ast.IndexExpr{
+			X: &ast.SelectorExpr{
+				X:   scope.Import("github.com/smtdfc/dix/di"),
+				Sel: ast.NewIdent("Singleton"),
+			},
+			Index: typeExpr,
+		}}
+	}
+	return zeroExpr(dep.Type, typeExpr)
+}
@@ -0,0 +1,79 @@
+package generator
+
+import "testing"
+
+const optionalStore = `package store
+
+import "github.com/smtdfc/dix/di"
+
+type DB struct{}
+
+// @Injectable
+func NewDB() *DB { return &DB{} }
+
+type Metrics struct{ Name string }
+
+type Service struct {
+	Metrics *Metrics
+	Shared  di.Singleton[*Metrics]
+}
+
+// @Injectable
+// @Root
+// @Optional metrics
+// @Optional shared
+func NewService(db *DB, metrics *Metrics, shared di.Singleton[*Metrics]) *Service {
+	return &Service{Metrics: metrics, Shared: shared}
+}
+`
+
+const optionalMain = `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+)
+
+func main() {
+	s := generated.Root()
+	if s.Metrics == nil {
+		fmt.Println(s.Shared.Get() == nil)
+		return
+	}
+	fmt.Println(s.Metrics.Name, s.Shared.Get().Name)
+}
+`
+
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics string
+		want    string
+	}{
+		{
+			name:    "provided",
+			metrics: "// @Injectable\nfunc NewMetrics() *Metrics { return &Metrics{Name: \"prometheus\"} }\n",
+			want:    "prometheus prometheus\n",
+		},
+		{
+			name: "absent",
+			want: "true\n",
+		},
+		{
+			name:    "disabled",
+			metrics: "// @Injectable\n// @Disable\nfunc NewMetrics() *Metrics { return &Metrics{Name: \"prometheus\"} }\n",
+			want:    "true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"store/store.go": optionalStore + "\n" + tt.metrics}
+			code := generateFiles(t, NewGenerator(), files)
+			if got := runGenerated(t, files, code, optionalMain); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
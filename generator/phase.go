@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const phaseHookName = "PhaseHook"
+
+// assignPhases gives every provider of graph the index of its phase in
+// Generator.Phases. A provider without @Phase joins the latest phase of its
+// dependencies, or the first phase. A provider may not depend on one of a
+// later phase, since that one would have to be built first.
+func (g *Generator) assignPhases(graph *Graph) error {
+	index := make(map[string]int, len(g.Phases))
+	for i, name := range g.Phases {
+		index[name] = i
+	}
+
+	phases := make(map[*parser.Provider]int)
+	var visit func(n *Node) (int, error)
+	visit = func(n *Node) (int, error) {
+		if phase, ok := phases[n.Provider]; ok {
+			return phase, nil
+		}
+		// Cycles are reported by Sort.
+		phases[n.Provider] = 0
+
+		declared := -1
+		if n.Provider.Phase != "" {
+			i, ok := index[n.Provider.Phase]
+			if !ok {
+				return 0, NewGenerateError(
+					ErrorValidation,
+					fmt.Sprintf("@Phase %s is not listed in the configured phases", n.Provider.Phase),
+					n.Provider.Name,
+					"",
+					nil,
+				)
+			}
+			declared = i
+		}
+
+		phase := 0
+		for _, dep := range n.Deps {
+			d, err := visit(dep)
+			if err != nil {
+				return 0, err
+			}
+			if declared >= 0 && d > declared {
+				return 0, NewGenerateError(
+					ErrorDependencyResolve,
+					fmt.Sprintf("provider of phase %s depends on a provider of the later phase %s", g.Phases[declared], g.Phases[d]),
+					n.Provider.Name,
+					dep.Provider.Name,
+					nil,
+				)
+			}
+			phase = max(phase, d)
+		}
+		if declared >= 0 {
+			phase = declared
+		}
+
+		phases[n.Provider] = phase
+		return phase, nil
+	}
+
+	if _, err := visit(graph.Root); err != nil {
+		return err
+	}
+	graph.phases = phases
+	return nil
+}
+
+// phaseHookStmt calls PhaseHook with the name of the phase about to start.
+func (g *Generator) phaseHookStmt(phase int, scope *Scope) ast.Stmt {
+	scope.UsesPhaseHook = true
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun:  ast.NewIdent(phaseHookName),
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", g.Phases[phase])}},
+		},
+	}
+}
+
+// GeneratePhaseHookDecl declares the PhaseHook variable called by Root at
+// the start of each phase. It does nothing by default; applications may
+// replace it before calling Root to run code between phases.
+func (g *Generator) GeneratePhaseHookDecl() *ast.GenDecl {
+	return &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent(phaseHookName)},
+				Values: []ast.Expr{
+					&ast.FuncLit{
+						Type: &ast.FuncType{
+							Params: &ast.FieldList{
+								List: []*ast.Field{
+									{Names: []*ast.Ident{ast.NewIdent("phase")}, Type: ast.NewIdent("string")},
+								},
+							},
+						},
+						Body: &ast.BlockStmt{},
+					},
+				},
+			},
+		},
+	}
+}
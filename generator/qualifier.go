@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// qualifiedKey indexes providers and generated variables by type and
+// @Named qualifier, so that qualified providers of a type never stand in
+// for each other or for the unqualified one.
+func qualifiedKey(t *parser.TypeInfo, qualifier string) string {
+	if qualifier == "" {
+		return t.Signature()
+	}
+	return t.Signature() + "@" + qualifier
+}
+
+func providerKey(p *parser.Provider) string {
+	return qualifiedKey(p.Return.Type, p.Qualifier)
+}
+
+func depKey(dep *parser.Dependency) string {
+	return qualifiedKey(dep.Type, dep.Qualifier)
+}
+
+// toggledDepKey is depKey for the other pointer level of dep's type.
+func toggledDepKey(dep *parser.Dependency) string {
+	return qualifiedKey(togglePointer(dep.Type), dep.Qualifier)
+}
+
+// qualifiersOf returns the sorted @Named qualifiers of the providers of
+// dep's type, to explain why an unqualified dependency is not found.
+func (m ProviderMap) qualifiersOf(dep *parser.Dependency) []string {
+	names := []string{}
+	for _, sig := range []string{dep.Type.Signature(), togglePointer(dep.Type).Signature()} {
+		for key := range m {
+			if qualifier, ok := strings.CutPrefix(key, sig+"@"); ok {
+				names = append(names, qualifier)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
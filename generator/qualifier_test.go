@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const qualifierStore = `package store
+
+type DB struct{ Name string }
+
+// @Injectable
+// @Named primary
+func NewPrimary() *DB { return &DB{Name: "primary"} }
+
+// @Injectable
+// @Named replica
+func NewReplica() *DB { return &DB{Name: "replica"} }
+
+type App struct{ Write, Read *DB }
+`
+
+// TestNamedSelection expects each parameter to receive the provider its
+// @Use annotation names, whatever the parameter order.
+func TestNamedSelection(t *testing.T) {
+	files := map[string]string{
+		"store/store.go": qualifierStore + `
+// @Injectable
+// @Root
+// @Use read replica
+// @Use write primary
+func NewApp(read *DB, write *DB) *App { return &App{Write: write, Read: read} }
+`,
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/generated"
+)
+
+func main() {
+	app := generated.Root()
+	fmt.Println(app.Write.Name, app.Read.Name)
+}
+`
+
+	code := generateFiles(t, NewGenerator(), files)
+	got := runGenerated(t, files, code, main)
+	if want := "primary replica\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestNamedWithoutUse(t *testing.T) {
+	metadata := parseFiles(t, map[string]string{
+		"store/store.go": qualifierStore + `
+// @Injectable
+// @Root
+func NewApp(db *DB) *App { return &App{Write: db, Read: db} }
+`,
+	})
+
+	_, err := NewGenerator().Generate(metadata)
+	if err == nil || !strings.Contains(err.Error(), "dependency is only provided with @Named primary, replica; select one with @Use") {
+		t.Fatalf("err = %v, want a missing @Use error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+const (
+	registryVarName  = "registry"
+	registryFuncName = "Get"
+)
+
+// registryAssignStmt stores the instance held by ident under the given
+// provider ID.
+func registryAssignStmt(id string, ident *ast.Ident) ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{
+			&ast.IndexExpr{
+				X:     ast.NewIdent(registryVarName),
+				Index: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", id)},
+			},
+		},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{ident},
+	}
+}
+
+// GenerateRegistryDecls declares the map filled by Root when Registry is
+// enabled and the Get function reading it.
+func (g *Generator) GenerateRegistryDecls() []ast.Decl {
+	anyMap := &ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("any")}
+	name := ast.NewIdent("name")
+	value := ast.NewIdent("value")
+	ok := ast.NewIdent("ok")
+
+	return []ast.Decl{
+		&ast.GenDecl{
+			Tok: token.VAR,
+			Specs: []ast.Spec{
+				&ast.ValueSpec{
+					Names:  []*ast.Ident{ast.NewIdent(registryVarName)},
+					Values: []ast.Expr{&ast.CompositeLit{Type: anyMap}},
+				},
+			},
+		},
+		&ast.FuncDecl{
+			Name: ast.NewIdent(registryFuncName),
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{
+					List: []*ast.Field{{Names: []*ast.Ident{name}, Type: ast.NewIdent("string")}},
+				},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: ast.NewIdent("any")}, {Type: ast.NewIdent("bool")}},
+				},
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.AssignStmt{
+						Lhs: []ast.Expr{value, ok},
+						Tok: token.DEFINE,
+						Rhs: []ast.Expr{&ast.IndexExpr{X: ast.NewIdent(registryVarName), Index: name}},
+					},
+					&ast.ReturnStmt{Results: []ast.Expr{value, ok}},
+				},
+			},
+		},
+	}
+}
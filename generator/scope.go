@@ -3,41 +3,205 @@ package generator
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/smtdfc/dix/parser"
 )
 
+// Scope belongs to a single generation and is not safe for concurrent use.
 type Scope struct {
 	Imports      map[string]*ast.Ident
 	Names        map[string]*ast.Ident
-	Counter      int
 	UniqueIdents map[string]int
+	Decorators   map[string][]*parser.Provider
+
+	// LocalPath is the import path of the generated package. Its own
+	// identifiers are referenced without an import.
+	LocalPath string
+
+	// ImportAlias, when not nil, names imported packages instead of the last
+	// element of their import path. See Generator.ImportAlias.
+	ImportAlias func(importPath string) string
+
+	// Helpers are the constructor functions declared for hoisted singleton
+	// dependencies, in order of first use. See Generator.HoistSingletons.
+	Helpers     []*ast.FuncDecl
+	helperNames map[string]*ast.Ident
+
+	// memoized holds the instances shared by @Memoize parameters in the
+	// root function being generated, by provider. memoizedStmts declares
+	// those not yet emitted.
+	memoized      map[string]*ast.Ident
+	memoizedStmts []ast.Stmt
+
+	// containerFields are the instances held by the generated Container,
+	// see Generator.Container.
+	containerFields []containerField
+
+	// UsesSoftErrorHook is set once a @SoftError provider call has been
+	// generated, so the caller knows to declare SoftErrorHook.
+	UsesSoftErrorHook bool
+
+	// UsesPhaseHook is set once a call to PhaseHook has been generated.
+	UsesPhaseHook bool
+
+	// UsesHealthChecks is set when a provider declares @Health, so root
+	// functions fill the health check list.
+	UsesHealthChecks bool
+
+	// used holds every identifier declared in the generated file, so that
+	// variables and import aliases never shadow or redeclare each other.
+	used map[string]bool
+}
+
+// reservedIdents are declared by the generated file itself. The root
+// function name is configurable and claimed by GenerateParts.
+var reservedIdents = []string{softErrorHookName, phaseHookName, hashConstName}
+
+// localIdents are parameters and variables of generated function bodies.
+// Default import aliases never match them, but ImportAlias results could.
+var localIdents = map[string]bool{
+	loggerParamName: true, contextParamName: true, "phase": true, "value": true, "err": true, "provider": true, "name": true, "ok": true,
+}
+
+func (s *Scope) claim(name string) bool {
+	if s.used == nil {
+		s.used = make(map[string]bool)
+		for _, r := range reservedIdents {
+			s.used[r] = true
+		}
+	}
+	if s.used[name] || token.IsKeyword(name) {
+		return false
+	}
+	s.used[name] = true
+	return true
+}
+
+// sanitizeIdent turns a type name such as `Box[int]` or `[]Repo` into a
+// valid Go identifier.
+func sanitizeIdent(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	name = strings.Trim(name, "_")
+
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "v" + name
+	}
+	return name
 }
 
 func (s *Scope) UniqueIdent(typeName string) *ast.Ident {
 	if s.UniqueIdents == nil {
 		s.UniqueIdents = make(map[string]int)
 	}
-	s.UniqueIdents[typeName]++
-	name := fmt.Sprintf("%s%d", typeName, s.UniqueIdents[typeName]-1)
-	return ast.NewIdent(name)
+	base := sanitizeIdent(typeName)
+	for {
+		s.UniqueIdents[base]++
+		name := fmt.Sprintf("%s%d", base, s.UniqueIdents[base]-1)
+		if s.claim(name) {
+			return ast.NewIdent(name)
+		}
+	}
+}
+
+var majorVersionRegex = regexp.MustCompile(`^v[0-9]+$`)
+var versionSuffixRegex = regexp.MustCompile(`\.v[0-9]+$`)
+
+// importAliasCandidates derives aliases from an import path alone, so that
+// a package keeps its alias when other providers are added or removed: the
+// last path element, skipping major version elements like v2 and suffixes
+// like .v3, then that element prefixed with its parent.
+func importAliasCandidates(importPath string) []string {
+	elems := strings.Split(importPath, "/")
+	last := len(elems) - 1
+	if last > 0 && majorVersionRegex.MatchString(elems[last]) {
+		last--
+	}
+
+	base := versionSuffixRegex.ReplaceAllString(elems[last], "")
+	candidates := []string{sanitizeIdent(base)}
+	if last > 0 {
+		candidates = append(candidates, sanitizeIdent(elems[last-1]+base))
+	}
+	return candidates
+}
+
+// usableAlias reports whether name can be claimed as an import alias
+// without shadowing a predeclared or local identifier of the generated
+// code.
+func (s *Scope) usableAlias(name string) bool {
+	return types.Universe.Lookup(name) == nil && !localIdents[name] && s.claim(name)
 }
 
 func (s *Scope) Import(pkg string) *ast.Ident {
 	if ident, ok := s.Imports[pkg]; ok {
 		return ident
-	} else {
-		s.Counter++
-		s.Imports[pkg] = &ast.Ident{
-			Name: fmt.Sprintf("pkg%d", s.Counter),
+	}
+
+	s.Imports[pkg] = ast.NewIdent(s.claimImportAlias(pkg))
+	return s.Imports[pkg]
+}
+
+func (s *Scope) claimImportAlias(pkg string) string {
+	candidates := importAliasCandidates(pkg)
+	if s.ImportAlias != nil {
+		if base := s.ImportAlias(pkg); base != "" {
+			candidates = []string{sanitizeIdent(base)}
 		}
+	}
+
+	for _, c := range candidates {
+		if s.usableAlias(c) {
+			return c
+		}
+	}
+	for n := 2; ; n++ {
+		if c := fmt.Sprintf("%s%d", candidates[0], n); s.usableAlias(c) {
+			return c
+		}
+	}
+}
+
+// resolveImportAliases gives the aliases out again in order of import path,
+// so that when two packages share a candidate alias the one sorted first
+// keeps it, whichever was imported first. The identifiers returned by Import
+// are renamed in place, which updates the generated code using them.
+func (s *Scope) resolveImportAliases() {
+	paths := make([]string, 0, len(s.Imports))
+	for path, ident := range s.Imports {
+		paths = append(paths, path)
+		delete(s.used, ident.Name)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		s.Imports[path].Name = s.claimImportAlias(path)
+	}
+}
 
-		return s.Imports[pkg]
+// Qualify refers to the package-level identifier name of package pkg,
+// importing pkg unless it is the generated package itself.
+func (s *Scope) Qualify(pkg, name string) ast.Expr {
+	if pkg == s.LocalPath {
+		return ast.NewIdent(name)
 	}
+	return &ast.SelectorExpr{X: s.Import(pkg), Sel: ast.NewIdent(name)}
 }
 
 func NewScope() *Scope {
 	return &Scope{
-		Counter: 0,
-		Imports: make(map[string]*ast.Ident),
-		Names:   make(map[string]*ast.Ident),
+		Imports:    make(map[string]*ast.Ident),
+		Names:      make(map[string]*ast.Ident),
+		Decorators: make(map[string][]*parser.Provider),
 	}
 }
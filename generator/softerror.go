@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const softErrorHookName = "SoftErrorHook"
+
+// softErrorExpr wraps the (T, error) call of a @SoftError provider in a
+// function literal that reports the error through SoftErrorHook and
+// continues with the zero value of T.
+func (g *Generator) softErrorExpr(provider *parser.Provider, call ast.Expr, scope *Scope) ast.Expr {
+	scope.UsesSoftErrorHook = true
+
+	value := ast.NewIdent("value")
+	errIdent := ast.NewIdent("err")
+
+	return &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{},
+				Results: &ast.FieldList{
+					List: []*ast.Field{{Type: g.TypeToASTExpr(provider.Return.Type, scope)}},
+				},
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.AssignStmt{
+						Lhs: []ast.Expr{value, errIdent},
+						Tok: token.DEFINE,
+						Rhs: []ast.Expr{call},
+					},
+					&ast.IfStmt{
+						Cond: &ast.BinaryExpr{X: errIdent, Op: token.NEQ, Y: ast.NewIdent("nil")},
+						Body: &ast.BlockStmt{
+							List: []ast.Stmt{
+								&ast.ExprStmt{
+									X: &ast.CallExpr{
+										Fun: ast.NewIdent(softErrorHookName),
+										Args: []ast.Expr{
+											&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", ProviderID(provider))},
+											errIdent,
+										},
+									},
+								},
+								&ast.ReturnStmt{Results: []ast.Expr{g.zeroValueExpr(provider.Return.Type, scope)}},
+							},
+						},
+					},
+					&ast.ReturnStmt{Results: []ast.Expr{value}},
+				},
+			},
+		},
+	}
+}
+
+// GenerateSoftErrorHookDecl declares the SoftErrorHook variable used by
+// @SoftError providers. By default it logs the error with the standard
+// library logger; applications may replace it before calling Root.
+func (g *Generator) GenerateSoftErrorHookDecl(scope *Scope) *ast.GenDecl {
+	provider := ast.NewIdent("provider")
+	errIdent := ast.NewIdent("err")
+
+	return &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent(softErrorHookName)},
+				Values: []ast.Expr{
+					&ast.FuncLit{
+						Type: &ast.FuncType{
+							Params: &ast.FieldList{
+								List: []*ast.Field{
+									{Names: []*ast.Ident{provider}, Type: ast.NewIdent("string")},
+									{Names: []*ast.Ident{errIdent}, Type: ast.NewIdent("error")},
+								},
+							},
+						},
+						Body: &ast.BlockStmt{
+							List: []ast.Stmt{
+								&ast.ExprStmt{
+									X: &ast.CallExpr{
+										Fun: &ast.SelectorExpr{
+											X:   scope.Import("log"),
+											Sel: ast.NewIdent("Printf"),
+										},
+										Args: []ast.Expr{
+											&ast.BasicLit{Kind: token.STRING, Value: `"dix: provider %s failed: %v"`},
+											provider,
+											errIdent,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package generator
+
+import "time"
+
+// Timings breaks the duration of Generate down by phase.
+type Timings struct {
+	Graph   time.Duration
+	Sort    time.Duration
+	Codegen time.Duration
+}
+
+// recordPhase applies set to Timings when they were requested.
+func (g *Generator) recordPhase(set func(t *Timings)) {
+	if g.Timings != nil {
+		set(g.Timings)
+	}
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// tracef writes one line of the resolution trace when Trace is set.
+func (g *Generator) tracef(format string, args ...any) {
+	if g.Trace != nil {
+		fmt.Fprintf(g.Trace, format+"\n", args...)
+	}
+}
+
+// traceOrder explains the position of every provider built by the function
+// name: its dependencies come first, then phase, @Order and provider ID
+// break ties.
+func (g *Generator) traceOrder(name string, graph *Graph, sorted []*parser.Provider) {
+	if g.Trace == nil {
+		return
+	}
+
+	deps := make(map[*parser.Provider][]string)
+	seen := make(map[*Node]bool)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		listed := make(map[*Node]bool)
+		for _, d := range n.Deps {
+			if !listed[d] {
+				listed[d] = true
+				deps[n.Provider] = append(deps[n.Provider], ProviderID(d.Provider))
+			}
+			walk(d)
+		}
+	}
+	walk(graph.Root)
+
+	g.tracef("%s: %d providers", name, len(sorted))
+	for i, p := range sorted {
+		reason := "no dependencies"
+		if len(deps[p]) > 0 {
+			reason = "after " + strings.Join(deps[p], ", ")
+		}
+		phase := ""
+		if len(g.Phases) > 0 {
+			phase = fmt.Sprintf(", phase %s", g.Phases[graph.phases[p]])
+		}
+		g.tracef("  %d. %s (%s%s, order %d)", i+1, ProviderID(p), reason, phase, p.Order)
+	}
+}
+
+// traceDep records how dep of provider is passed.
+func (g *Generator) traceDep(provider *parser.Provider, dep *parser.Dependency, providerMap ProviderMap) {
+	if g.Trace == nil {
+		return
+	}
+
+	var decision string
+	switch {
+	case dep.Literal != "":
+		decision = "literal " + dep.Literal
+	case dep.Env != "":
+		decision = "environment variable " + dep.Env
+	case dep.IsContext:
+		decision = "Root parameter " + contextParamName
+	default:
+		to, adapt, ok := providerMap.resolve(dep)
+		switch {
+		case providerMap.isAbsent(dep):
+			decision = "zero value, @Optional without a provider"
+		case !ok && isLoggerDep(dep):
+			decision = "Root parameter " + loggerParamName
+		case !ok:
+			decision = "unresolved"
+		case dep.Memoize:
+			decision = "memoized instance from " + ProviderID(to)
+		case dep.IsSingleton:
+			decision = "new instance from " + ProviderID(to)
+		default:
+			decision = "shared instance from " + ProviderID(to)
+		}
+		switch adapt {
+		case adaptAddress:
+			decision += ", address taken"
+		case adaptDeref:
+			decision += ", dereferenced"
+		}
+	}
+
+	g.tracef("  %s: parameter %s: %s", ProviderID(provider), dep.Name, decision)
+}
+
+// traceImports lists the alias given to every imported package.
+func (g *Generator) traceImports(scope *Scope) {
+	if g.Trace == nil {
+		return
+	}
+
+	paths := make([]string, 0, len(scope.Imports))
+	for path := range scope.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	g.tracef("imports:")
+	for _, path := range paths {
+		g.tracef("  %s %q", scope.Imports[path].Name, path)
+	}
+}
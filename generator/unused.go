@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+// UnusedProviders returns the enabled providers that neither the @Root
+// provider nor any @Entry provider needs, directly or through other
+// providers, sorted by provider ID. @If alternatives count as used when
+// their fallback is.
+func UnusedProviders(metadata *parser.Metadata) []*parser.Provider {
+	providerMap := NewProviderMap(metadata.Providers)
+
+	used := make(map[*parser.Provider]bool)
+	var visit func(p *parser.Provider)
+	visit = func(p *parser.Provider) {
+		if used[p] {
+			return
+		}
+		used[p] = true
+
+		for _, dep := range p.Deps {
+			if dep.IsValue() {
+				continue
+			}
+			if to, ok := providerMap.Resolve(dep); ok {
+				visit(to)
+			}
+		}
+		for _, alt := range p.Alternatives {
+			visit(alt)
+		}
+	}
+
+	if metadata.Root != nil {
+		visit(metadata.Root)
+	}
+	for _, e := range entryProviders(metadata) {
+		visit(e)
+	}
+
+	unused := []*parser.Provider{}
+	for _, p := range metadata.Providers {
+		if !used[p] && !p.IsDisable {
+			unused = append(unused, p)
+		}
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		return ProviderID(unused[i]) < ProviderID(unused[j])
+	})
+	return unused
+}
+
+// checkUnused enforces FailOnUnused, reporting every provider returned by
+// UnusedProviders.
+func (g *Generator) checkUnused(metadata *parser.Metadata) error {
+	if !g.FailOnUnused {
+		return nil
+	}
+
+	errs := []error{}
+	for _, p := range UnusedProviders(metadata) {
+		errs = append(errs, NewGenerateError(
+			ErrorValidation,
+			fmt.Sprintf("provider is never used by @Root or an @Entry (%s:%d)", p.File, p.Line),
+			ProviderID(p),
+			"",
+			nil,
+		))
+	}
+	return errors.Join(errs...)
+}
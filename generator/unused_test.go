@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const unusedStore = `package store
+
+type DB struct{}
+
+// @Injectable
+func NewDB() *DB { return &DB{} }
+
+type Cache struct{}
+
+// @Injectable
+func NewCache() *Cache { return &Cache{} }
+
+// @Injectable
+// @If UseRedis
+func NewRedisCache() *Cache { return &Cache{} }
+
+func UseRedis() bool { return false }
+
+type Queue struct{}
+
+// @Injectable
+func NewQueue() *Queue { return &Queue{} }
+
+type Worker struct{}
+
+// @Injectable
+// @Entry Worker
+func NewWorker(q *Queue) *Worker { return &Worker{} }
+
+type Mailer struct{}
+
+// @Injectable
+func NewMailer(db *DB) *Mailer { return &Mailer{} }
+
+type Report struct{}
+
+// @Injectable
+func NewReport(m *Mailer) *Report { return &Report{} }
+
+type Legacy struct{}
+
+// @Injectable
+// @Disable
+func NewLegacy() *Legacy { return &Legacy{} }
+
+type App struct{}
+
+// @Injectable
+// @Root
+func NewApp(db *DB, c *Cache) *App { return &App{} }
+`
+
+// TestUnusedProviders expects the providers reached from neither @Root nor
+// an @Entry to be reported, leaving out @If alternatives of used providers
+// and disabled ones.
+func TestUnusedProviders(t *testing.T) {
+	metadata := parseFiles(t, map[string]string{"store/store.go": unusedStore})
+
+	ids := []string{}
+	for _, p := range UnusedProviders(metadata) {
+		ids = append(ids, ProviderID(p))
+	}
+	want := []string{"example.com/app/store.NewMailer", "example.com/app/store.NewReport"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("unused = %v, want %v", ids, want)
+	}
+}
+
+func TestFailOnUnused(t *testing.T) {
+	metadata := parseFiles(t, map[string]string{"store/store.go": unusedStore})
+
+	if _, err := NewGenerator().Generate(metadata); err != nil {
+		t.Fatalf("unused providers fail generation without FailOnUnused: %v", err)
+	}
+
+	g := NewGenerator()
+	g.FailOnUnused = true
+	_, err := g.Generate(metadata)
+	if err == nil {
+		t.Fatal("Generate returned no error with FailOnUnused")
+	}
+	for _, id := range []string{"example.com/app/store.NewMailer", "example.com/app/store.NewReport"} {
+		if !strings.Contains(err.Error(), "provider is never used by @Root or an @Entry") || !strings.Contains(err.Error(), id) {
+			t.Errorf("err = %v, want %s reported as unused", err, id)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package generator
+
+import "github.com/smtdfc/dix/parser"
+
+// Validate runs the checks of Generate without rendering the file: names,
+// limits, missing, disabled and @Final dependencies, cycles, phases and
+// internal imports, for the root function and every @Entry and Build
+// function. Problems found while building a graph are reported together.
+func (g *Generator) Validate(metadata *parser.Metadata) error {
+	parts, err := g.GenerateParts(metadata)
+	if err != nil {
+		return err
+	}
+	return g.checkInternalImports(parts.Scope)
+}
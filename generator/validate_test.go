@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const validateApp = `
+type App struct{}
+
+// @Injectable
+// @Root
+func NewApp(r *Repo) *App { return &App{} }
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		store     string
+		configure func(g *Generator)
+		want      []string
+	}{
+		{
+			name:  "clean",
+			store: "// @Injectable\nfunc NewRepo() *Repo { return &Repo{} }\n",
+		},
+		{
+			name:  "missing dependency",
+			store: "type DB struct{}\n\n// @Injectable\nfunc NewRepo(db *DB) *Repo { return &Repo{} }\n",
+			want:  []string{"provider not found for dependency [provider=NewRepo]"},
+		},
+		{
+			name:  "disabled dependency",
+			store: "// @Injectable\n// @Disable\nfunc NewRepo() *Repo { return &Repo{} }\n",
+			want:  []string{"the only provider for this dependency, example.com/app/internal/store.NewRepo, is marked @Disable"},
+		},
+		{
+			name:  "final dependency",
+			store: "// @Injectable\n// @Final\nfunc NewRepo() *Repo { return &Repo{} }\n",
+			want:  []string{"example.com/app/internal/store.NewRepo is marked @Final and cannot be used as a dependency"},
+		},
+		{
+			name:  "self dependency",
+			store: "// @Injectable\nfunc NewRepo(r *Repo) *Repo { return r }\n",
+			want:  []string{"cannot depend on the type it provides"},
+		},
+		{
+			name:  "cycle",
+			store: "type DB struct{}\n\n// @Injectable\nfunc NewRepo(db *DB) *Repo { return &Repo{} }\n\n// @Injectable\nfunc NewDB(r *Repo) *DB { return &DB{} }\n",
+			want:  []string{"circular dependency detected"},
+		},
+		{
+			name:      "invalid name",
+			store:     "// @Injectable\nfunc NewRepo() *Repo { return &Repo{} }\n",
+			configure: func(g *Generator) { g.RootName = "new-root" },
+			want:      []string{`"new-root" is not a valid Go identifier`},
+		},
+		{
+			name:      "provider limit",
+			store:     "type DB struct{}\n\n// @Injectable\nfunc NewRepo(db *DB) *Repo { return &Repo{} }\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+			configure: func(g *Generator) { g.MaxProviders = 1 },
+			want:      []string{"2 providers found, more than the limit of 1"},
+		},
+		{
+			name:      "depth limit",
+			store:     "type DB struct{}\n\n// @Injectable\nfunc NewRepo(db *DB) *Repo { return &Repo{} }\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+			configure: func(g *Generator) { g.MaxDepth = 1 },
+			want:      []string{"exceeds the limit of 1"},
+		},
+		{
+			name:      "unused provider",
+			store:     "type DB struct{}\n\n// @Injectable\nfunc NewRepo() *Repo { return &Repo{} }\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+			configure: func(g *Generator) { g.FailOnUnused = true },
+			want:      []string{"provider is never used by @Root or an @Entry"},
+		},
+		{
+			name:      "unknown phase",
+			store:     "// @Injectable\n// @Phase late\nfunc NewRepo() *Repo { return &Repo{} }\n",
+			configure: func(g *Generator) { g.Phases = []string{"early"} },
+			want:      []string{"@Phase late is not listed in the configured phases"},
+		},
+		{
+			name:      "phase order",
+			store:     "type DB struct{}\n\n// @Injectable\n// @Phase early\nfunc NewRepo(db *DB) *Repo { return &Repo{} }\n\n// @Injectable\n// @Phase late\nfunc NewDB() *DB { return &DB{} }\n",
+			configure: func(g *Generator) { g.Phases = []string{"early", "late"} },
+			want:      []string{"provider of phase early depends on a provider of the later phase late"},
+		},
+		{
+			name:      "internal import",
+			store:     "// @Injectable\nfunc NewRepo() *Repo { return &Repo{} }\n",
+			configure: func(g *Generator) { g.OutputImportPath = "example.com/other/generated" },
+			want:      []string{"cannot import internal package example.com/app/internal/store"},
+		},
+		{
+			name:  "several problems",
+			store: "type DB struct{}\n\ntype Cache struct{}\n\n// @Injectable\nfunc NewRepo(db *DB, c *Cache) *Repo { return &Repo{} }\n",
+			want:  []string{"db (type of *DB in package example.com/app/internal/store)", "c (type of *Cache in package example.com/app/internal/store)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := parseFiles(t, map[string]string{
+				"internal/store/store.go": "package store\n\ntype Repo struct{}\n\n" + tt.store + validateApp,
+			})
+
+			g := NewGenerator()
+			if tt.configure != nil {
+				tt.configure(g)
+			}
+			err := g.Validate(metadata)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate returned no error, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("err = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
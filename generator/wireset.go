@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/smtdfc/dix/parser"
+)
+
+const wireImportPath = "github.com/google/wire"
+
+// ExportWireSet renders the parsed providers as a google/wire provider set,
+// to help migrating between the two tools. Features without a wire
+// equivalent are listed as comments at the top of the file and left out.
+func ExportWireSet(metadata *parser.Metadata) (string, error) {
+	providers := append([]*parser.Provider{}, metadata.Providers...)
+	if metadata.Root != nil {
+		providers = append(providers, metadata.Root)
+	}
+	sort.SliceStable(providers, func(i, j int) bool {
+		return ProviderID(providers[i]) < ProviderID(providers[j])
+	})
+
+	scope := NewScope()
+	providerMap := NewProviderMap(metadata.Providers)
+	entries := []*parser.Provider{}
+	warnings := []string{}
+
+	for _, p := range providers {
+		id := ProviderID(p)
+		if p.IsDisable {
+			warnings = append(warnings, fmt.Sprintf("%s is disabled and was skipped", id))
+			continue
+		}
+		if len(p.FieldPath) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s is an @Expose field; use wire.FieldsOf manually", id))
+			continue
+		}
+		if p.IsBinding {
+			warnings = append(warnings, fmt.Sprintf("%s is a @Bind binding; use wire.Bind manually", id))
+			continue
+		}
+		if p.Method != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is a method provider; wrap it in a function to use it with wire", id))
+			continue
+		}
+		if len(p.Alternatives) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s has @If alternatives that wire cannot select at runtime; only the fallback is listed", id))
+		}
+		if p.SoftError {
+			warnings = append(warnings, fmt.Sprintf("%s is a @SoftError provider; wire aborts on its error instead of continuing", id))
+		}
+		for _, dep := range p.Deps {
+			if _, exact := providerMap[depKey(dep)]; !exact && !dep.IsValue() {
+				if _, ok := providerMap.Resolve(dep); ok {
+					warnings = append(warnings, fmt.Sprintf("%s parameter %s differs from its provider by a pointer level, which wire does not adapt", id, dep.Name))
+				}
+			}
+			if dep.IsSingleton {
+				warnings = append(warnings, fmt.Sprintf("%s takes di.Singleton parameter %s, which needs its own wire provider", id, dep.Name))
+			}
+			if dep.Literal != "" {
+				warnings = append(warnings, fmt.Sprintf("%s binds parameter %s to literal %s, which needs its own wire provider", id, dep.Name, dep.Literal))
+			}
+			if dep.Env != "" {
+				warnings = append(warnings, fmt.Sprintf("%s reads parameter %s from environment variable %s, which needs its own wire provider", id, dep.Name, dep.Env))
+			}
+		}
+
+		scope.Import(p.PackagePath)
+		entries = append(entries, p)
+	}
+
+	for _, d := range metadata.Decorators {
+		warnings = append(warnings, fmt.Sprintf("%s is a @Decorator and has no wire equivalent", ProviderID(d)))
+	}
+
+	var b strings.Builder
+	b.WriteString("//go:build wireinject\n\n")
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "// dix: %s\n", w)
+	}
+	if len(warnings) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("package generated\n\n")
+
+	scope.resolveImportAliases()
+	paths := make([]string, 0, len(scope.Imports))
+	for path := range scope.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	b.WriteString("import (\n")
+	fmt.Fprintf(&b, "\t%q\n", wireImportPath)
+	for _, path := range paths {
+		fmt.Fprintf(&b, "\t%s %q\n", scope.Imports[path].Name, path)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("var ProviderSet = wire.NewSet(\n")
+	for _, p := range entries {
+		fmt.Fprintf(&b, "\t%s.%s,\n", scope.Imports[p.PackagePath].Name, p.Name)
+	}
+	b.WriteString(")\n")
+
+	code, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", NewGenerateError(ErrorCodeGeneration, "failed to format wire provider set", "", "", err)
+	}
+
+	return string(code), nil
+}
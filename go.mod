@@ -2,17 +2,14 @@ module github.com/smtdfc/dix
 
 go 1.25.1
 
-require golang.org/x/tools v0.42.0
+require (
+	github.com/spf13/cobra v1.10.2
+	golang.org/x/mod v0.33.0
+	golang.org/x/tools v0.42.0
+)
 
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/fatih/color v1.18.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
-	golang.org/x/mod v0.33.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/sys v0.41.0 // indirect
 )
@@ -5,12 +5,53 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/parser"
 )
 
 const configFileName = "dix.config.json"
 
+const defaultOutputPath = "./generated/dix/root.go"
+
 type Config struct {
-	Output string `json:"output"`
+	Output          string                  `json:"output"`
+	SourceComments  bool                    `json:"source_comments"`
+	EmbedHash       bool                    `json:"embed_hash"`
+	Format          *generator.FormatConfig `json:"format,omitempty"`
+	FollowSymlinks  bool                    `json:"follow_symlinks"`
+	Registry        bool                    `json:"registry"`
+	Directives      bool                    `json:"directives"`
+	Exclude         []string                `json:"exclude,omitempty"`
+	HoistSingletons bool                    `json:"hoist_singletons"`
+	BuildFuncs      bool                    `json:"build_funcs"`
+	Container       bool                    `json:"container"`
+	Phases          []string                `json:"phases,omitempty"`
+	PackageName     string                  `json:"package_name,omitempty"`
+	RootName        string                  `json:"root_name,omitempty"`
+	RootAlias       string                  `json:"root_alias,omitempty"`
+	Roots           []string                `json:"roots,omitempty"`
+	Merge           parser.MergePolicy      `json:"merge,omitempty"`
+	MaxProviders    int                     `json:"max_providers"`
+	MaxDepth        int                     `json:"max_depth"`
+	FailOnUnused    bool                    `json:"fail_on_unused"`
+}
+
+// OutputPath returns the configured output file, or the default one.
+func (c *Config) OutputPath() string {
+	if c.Output == "" {
+		return defaultOutputPath
+	}
+	return c.Output
+}
+
+// MergePolicy returns how providers of the same type found under different
+// roots are combined. The default is to report them as errors.
+func (c *Config) MergePolicy() parser.MergePolicy {
+	if c.Merge == "" {
+		return parser.MergeError
+	}
+	return c.Merge
 }
 
 func ReadConfig() (*Config, error) {
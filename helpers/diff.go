@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiffGenerated compares code with the file at filePath, relative to the
+// working directory, and returns a line diff, or "" when they are equal. A
+// missing file counts as empty.
+func DiffGenerated(code string, filePath string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := os.ReadFile(filepath.Join(cwd, filePath))
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if string(existing) == code {
+		return "", nil
+	}
+
+	return diffLines(string(existing), code), nil
+}
+
+// diffLines returns the lines removed from a (prefixed with "-") and added
+// in b (prefixed with "+"), based on their longest common subsequence.
+func diffLines(a, b string) string {
+	x := strings.Split(a, "\n")
+	y := strings.Split(b, "\n")
+
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "-%d: %s\n", i+1, x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+%d: %s\n", j+1, y[j])
+			j++
+		}
+	}
+
+	return out.String()
+}
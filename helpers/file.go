@@ -1,30 +1,100 @@
 package helpers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
 
+// WriteTextFile writes text to filePath, relative to the working directory
+// unless absolute, creating parent directories as needed. The text goes to
+// a temporary file that is then renamed over filePath, so an interrupted
+// write never leaves a truncated file behind.
 func WriteTextFile(text string, filePath string) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return err
+	outputPath := filePath
+	if !filepath.IsAbs(outputPath) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		outputPath = filepath.Join(cwd, filePath)
 	}
 
-	bytes := []byte(text)
-
-	outputPath := filepath.Join(cwd, filePath)
-
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	err = os.WriteFile(outputPath, bytes, 0644)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(outputPath)+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(text); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
 
 	return nil
 }
+
+// WriteGeneratedFile writes generated code like WriteTextFile, but refuses
+// to replace an existing file that lacks the "Code generated ... DO NOT
+// EDIT." header, such as a hand-written file at a mistyped output path,
+// unless force is set.
+func WriteGeneratedFile(code string, filePath string, force bool) error {
+	if !force {
+		generated, err := isGeneratedFile(filePath)
+		if err != nil {
+			return err
+		}
+		if !generated {
+			return fmt.Errorf("%s exists and was not generated; remove it, change the output path or pass --force to overwrite it", filePath)
+		}
+	}
+
+	return WriteTextFile(code, filePath)
+}
+
+// legacyGeneratedHeader starts the files written by dix before they carried
+// a "Code generated" comment.
+const legacyGeneratedHeader = "//go:build !dix\n// +build !dix\n"
+
+// isGeneratedFile reports whether filePath is missing or holds a Go file
+// marked as generated, including dix output in the legacy format.
+func isGeneratedFile(filePath string) (bool, error) {
+	src, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if bytes.HasPrefix(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")), []byte(legacyGeneratedHeader)) {
+		return true, nil
+	}
+
+	file, err := goparser.ParseFile(token.NewFileSet(), filePath, src, goparser.PackageClauseOnly|goparser.ParseComments)
+	if err != nil {
+		return false, nil
+	}
+	return ast.IsGenerated(file), nil
+}
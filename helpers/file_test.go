@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const generatedCode = "// Code generated by dix. DO NOT EDIT.\n\npackage generated\n"
+
+func TestWriteGeneratedFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		err      string
+	}{
+		{name: "new file"},
+		{name: "generated file", existing: "// Code generated by dix. DO NOT EDIT.\n\npackage generated\n\nfunc Root() {}\n"},
+		{name: "legacy generated file", existing: "//go:build !dix\r\n// +build !dix\r\n\r\npackage generated\r\n"},
+		{name: "hand-written file", existing: "package generated\n\nfunc Root() {}\n", err: "exists and was not generated"},
+		{name: "hand-written file with force", existing: "package generated\n\nfunc Root() {}\n", force: true},
+		{name: "not Go", existing: "notes\n", err: "exists and was not generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "generated", "dix", "root.go")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := WriteGeneratedFile(generatedCode, path, tt.force)
+
+			got, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				if string(got) != tt.existing {
+					t.Errorf("file was overwritten with %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != generatedCode {
+				t.Errorf("file holds %q, want the generated code", got)
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("output directory holds %d entries, want only the output file", len(entries))
+			}
+		})
+	}
+}
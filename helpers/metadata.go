@@ -15,7 +15,7 @@ func SaveMetadata(metadata *parser.Metadata, name string) error {
 		return err
 	}
 
-	bytes, err := json.MarshalIndent(metadata, "", "  ")
+	bytes, err := ExportJSON(metadata)
 	if err != nil {
 		return err
 	}
@@ -35,15 +35,28 @@ func SaveMetadata(metadata *parser.Metadata, name string) error {
 	return nil
 }
 
+// ExportJSON encodes metadata as indented JSON. Providers and decorators
+// keep the order they were scanned in, since it matters to generation:
+// decorators are applied in that order, and of several unmarked providers
+// of a type the last one is used.
+func ExportJSON(metadata *parser.Metadata) ([]byte, error) {
+	return json.MarshalIndent(metadata, "", "  ")
+}
+
 func LoadMetadata(path string) (*parser.Metadata, error) {
 	bytes, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read metadata file: %w", err)
 	}
 
+	return ImportJSON(bytes)
+}
+
+// ImportJSON decodes metadata written by ExportJSON.
+func ImportJSON(data []byte) (*parser.Metadata, error) {
 	metadata := &parser.Metadata{}
 
-	err = json.Unmarshal(bytes, metadata)
+	err := json.Unmarshal(data, metadata)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode json : %w", err)
 	}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smtdfc/dix/generator"
+	"github.com/smtdfc/dix/parser"
+)
+
+var roundTripModule = fstest.MapFS{
+	"go.mod": {Data: []byte("module example.com/app\n\ngo 1.22\n")},
+	"store/store.go": {Data: []byte(`package store
+
+type DB struct{ Name string }
+
+// @Injectable
+// @Named replica
+func NewReplica() *DB { return &DB{Name: "replica"} }
+
+// @Injectable
+// @Named primary
+func NewPrimary() *DB { return &DB{Name: "primary"} }
+
+// @Decorator
+func Trace(db *DB) *DB { return db }
+
+// @Decorator
+func Audit(db *DB) *DB { return db }
+
+type Cache struct{}
+
+// @Injectable
+func NewZCache() *Cache { return &Cache{} }
+
+// Declared last, so it is used for *Cache.
+// @Injectable
+func NewACache() *Cache { return &Cache{} }
+`)},
+	"app/app.go": {Data: []byte(`package app
+
+import "example.com/app/store"
+
+type Config struct{}
+
+// @Injectable
+func NewConfig() (*Config, error) { return &Config{}, nil }
+
+type App struct{}
+
+// @Injectable
+// @Root
+// @Use primary primary
+// @Use replica replica
+func NewApp(cfg *Config, primary *store.DB, replica *store.DB, cache *store.Cache) *App { return &App{} }
+`)},
+}
+
+func TestImportJSONRoundTrip(t *testing.T) {
+	scanned, err := parser.NewParser().ParseFS(roundTripModule, ".")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want, err := generator.NewGenerator().Generate(scanned)
+	if err != nil {
+		t.Fatalf("generate from scan: %v", err)
+	}
+
+	data, err := ExportJSON(scanned)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	imported, err := ImportJSON(data)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	got, err := generator.NewGenerator().Generate(imported)
+	if err != nil {
+		t.Fatalf("generate from import: %v", err)
+	}
+	if !strings.Contains(want, "store.NewACache()") {
+		t.Fatalf("scanned metadata does not use the last declared *Cache provider:\n%s", want)
+	}
+	if got != want {
+		t.Errorf("code generated from imported metadata differs:\n--- scanned\n%s\n--- imported\n%s", want, got)
+	}
+
+	again, err := ExportJSON(imported)
+	if err != nil {
+		t.Fatalf("export imported: %v", err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Errorf("exporting imported metadata changed the JSON:\n%s\n---\n%s", data, again)
+	}
+}
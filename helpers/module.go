@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// OutputImportPath returns the import path of the package that a file
+// written to outputPath, relative to the working directory, belongs to.
+func OutputImportPath(outputPath string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	dir := cwd
+	for {
+		body, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(body)
+			if modulePath == "" {
+				return "", fmt.Errorf("go.mod in %s has no module directive", dir)
+			}
+
+			rel, err := filepath.Rel(dir, filepath.Dir(filepath.Join(cwd, outputPath)))
+			if err != nil {
+				return "", err
+			}
+			return path.Join(modulePath, filepath.ToSlash(rel)), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("reading go.mod: %w", err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found above %s", cwd)
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputImportPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		goMod string
+		isDir bool
+		want  string
+		err   string
+	}{
+		{name: "module", goMod: "module example.com/app\n", want: "example.com/app/generated/dix"},
+		{name: "no module directive", goMod: "go 1.25\n", err: "has no module directive"},
+		{name: "unreadable", isDir: true, err: "reading go.mod"},
+		{name: "missing", err: "no go.mod found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if above := goModAbove(dir); tt.err == "no go.mod found" && above != "" {
+				t.Skipf("%s is in the module of %s", dir, above)
+			}
+			switch {
+			case tt.isDir:
+				if err := os.Mkdir(filepath.Join(dir, "go.mod"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			case tt.goMod != "":
+				if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(tt.goMod), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Chdir(dir)
+
+			got, err := OutputImportPath("generated/dix/root.go")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// goModAbove returns the first go.mod found in a parent of dir, if any.
+func goModAbove(dir string) string {
+	for parent := filepath.Dir(dir); parent != dir; dir, parent = parent, filepath.Dir(parent) {
+		if _, err := os.Stat(filepath.Join(parent, "go.mod")); err == nil {
+			return filepath.Join(parent, "go.mod")
+		}
+	}
+	return ""
+}
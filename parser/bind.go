@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseBind returns a provider per @Bind interface of source, providing the
+// interface from the value of source so that dependencies on the interface
+// receive it. Interfaces are named like for @Implements.
+func (p *Parser) ParseBind(pkg *packages.Package, file *ast.File, fn *ast.FuncDecl, source *Provider, names []string) ([]*Provider, error) {
+	ret := pkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)
+	if ret == nil {
+		return nil, NewValidationError("cannot resolve provider return type", fn.Name.Name, "", source.File)
+	}
+
+	bindings := []*Provider{}
+	for _, name := range names {
+		obj, err := lookupInterface(pkg, file, name)
+		if err != nil {
+			return nil, NewValidationError(fmt.Sprintf("invalid @Bind %s: %v", name, err), fn.Name.Name, "", source.File)
+		}
+
+		if !types.Implements(ret, obj.Type().Underlying().(*types.Interface)) {
+			return nil, NewValidationError(
+				fmt.Sprintf("cannot @Bind %s: %s does not implement it", name, types.TypeString(ret, nil)),
+				fn.Name.Name,
+				"",
+				source.File,
+			)
+		}
+
+		bindings = append(bindings, &Provider{
+			Name:        fmt.Sprintf("%s.%s", source.Name, obj.Name()),
+			File:        source.File,
+			Line:        source.Line,
+			PackagePath: source.PackagePath,
+			PackageName: source.PackageName,
+			ParamCount:  1,
+			Deps: []*Dependency{
+				{
+					Name:      "source",
+					Type:      source.Return.Type,
+					Qualifier: source.Qualifier,
+				},
+			},
+			Return: &ReturnValue{
+				Type: &TypeInfo{
+					Name: obj.Name(),
+					Pkg:  obj.Pkg().Path(),
+				},
+			},
+			IsBinding:   true,
+			Qualifier:   source.Qualifier,
+			IsDisable:   source.IsDisable,
+			IsPrimary:   source.IsPrimary,
+			Annotations: []string{"@Bind " + name},
+		})
+	}
+
+	return bindings, nil
+}
+
+// validateBindings requires an interface bound by several enabled providers
+// to have a @Primary one, since silently picking the last binding would
+// depend on scan order.
+func validateBindings(metadata *Metadata) error {
+	bindings := make(map[string][]*Provider)
+	sigs := []string{}
+	for _, p := range metadata.Providers {
+		if !p.IsBinding || p.IsDisable {
+			continue
+		}
+		sig := p.Return.Type.Signature() + "@" + p.Qualifier
+		if _, ok := bindings[sig]; !ok {
+			sigs = append(sigs, sig)
+		}
+		bindings[sig] = append(bindings[sig], p)
+	}
+
+	errs := []error{}
+	for _, sig := range sigs {
+		bound := bindings[sig]
+		if len(bound) < 2 {
+			continue
+		}
+
+		hasPrimary := false
+		for _, p := range bound {
+			hasPrimary = hasPrimary || p.IsPrimary
+		}
+		if hasPrimary {
+			continue
+		}
+
+		second := bound[1]
+		errs = append(errs, NewValidationError(
+			fmt.Sprintf("%s is also bound by %s.%s; mark one of them @Primary", second.Return.Type.Name, bound[0].PackagePath, bound[0].Name),
+			second.Name,
+			"",
+			second.File,
+		))
+	}
+
+	return errors.Join(errs...)
+}
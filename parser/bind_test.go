@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func bindStore(primary string) string {
+	return `package store
+
+type Store interface{ Get() string }
+
+type Memory struct{}
+
+func (Memory) Get() string { return "memory" }
+
+// @Injectable
+// @Bind Store
+func NewMemory() *Memory { return &Memory{} }
+
+type Disk struct{}
+
+func (Disk) Get() string { return "disk" }
+
+// @Injectable
+// @Bind Store
+` + primary + `func NewDisk() *Disk { return &Disk{} }
+`
+}
+
+func TestBindWithoutPrimary(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{"store/store.go": bindStore("")})
+
+	_, err := quietParser().Parse(dir)
+	if err == nil || !strings.Contains(err.Error(), "Store is also bound by example.com/app/store.NewMemory.Store; mark one of them @Primary") {
+		t.Fatalf("err = %v, want an ambiguous binding error", err)
+	}
+}
+
+func TestBindWithPrimary(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{"store/store.go": bindStore("// @Primary\n")})
+
+	if _, err := quietParser().Parse(dir); err != nil {
+		t.Fatal(err)
+	}
+}
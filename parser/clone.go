@@ -0,0 +1,70 @@
+package parser
+
+// Clone returns a deep copy of the metadata, so it can be changed without
+// affecting the scan it came from.
+func (m *Metadata) Clone() *Metadata {
+	if m == nil {
+		return nil
+	}
+
+	return &Metadata{
+		Providers:  cloneProviders(m.Providers),
+		Root:       m.Root.Clone(),
+		Decorators: cloneProviders(m.Decorators),
+	}
+}
+
+func (p *Provider) Clone() *Provider {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	c.Deps = nil
+	for _, d := range p.Deps {
+		c.Deps = append(c.Deps, d.Clone())
+	}
+	if p.Return != nil {
+		c.Return = &ReturnValue{Type: p.Return.Type.Clone()}
+	}
+	c.FieldPath = append([]string(nil), p.FieldPath...)
+	c.Annotations = append([]string(nil), p.Annotations...)
+	c.Alternatives = cloneProviders(p.Alternatives)
+	c.Implements = nil
+	for _, t := range p.Implements {
+		c.Implements = append(c.Implements, t.Clone())
+	}
+
+	return &c
+}
+
+func (d *Dependency) Clone() *Dependency {
+	if d == nil {
+		return nil
+	}
+
+	c := *d
+	c.Type = d.Type.Clone()
+	return &c
+}
+
+func (t *TypeInfo) Clone() *TypeInfo {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
+
+func cloneProviders(providers []*Provider) []*Provider {
+	if providers == nil {
+		return nil
+	}
+
+	cloned := make([]*Provider, 0, len(providers))
+	for _, p := range providers {
+		cloned = append(cloned, p.Clone())
+	}
+	return cloned
+}
@@ -1,11 +1,29 @@
 package parser
 
 type Provider struct {
-	File        string        `json:"file"`
-	Name        string        `json:"name"`
-	Deps        []*Dependency `json:"deps"`
-	Return      *ReturnValue  `json:"return"`
-	PackagePath string        `json:"pkg_path"`
-	PackageName string        `json:"pkg_name"`
-	IsDisable   bool          `json:"is_disable"`
+	File         string        `json:"file"`
+	Line         int           `json:"line"`
+	Name         string        `json:"name"`
+	Deps         []*Dependency `json:"deps"`
+	Return       *ReturnValue  `json:"return"`
+	ParamCount   int           `json:"param_count"`
+	PackagePath  string        `json:"pkg_path"`
+	PackageName  string        `json:"pkg_name"`
+	IsDisable    bool          `json:"is_disable"`
+	IsPrimary    bool          `json:"is_primary,omitempty"`
+	IsFinal      bool          `json:"is_final,omitempty"`
+	FieldPath    []string      `json:"field_path,omitempty"`
+	IsBinding    bool          `json:"is_binding,omitempty"`
+	Qualifier    string        `json:"qualifier,omitempty"`
+	Method       string        `json:"method,omitempty"`
+	Annotations  []string      `json:"annotations,omitempty"`
+	Condition    string        `json:"condition,omitempty"`
+	Alternatives []*Provider   `json:"alternatives,omitempty"`
+	Implements   []*TypeInfo   `json:"implements,omitempty"`
+	SoftError    bool          `json:"soft_error,omitempty"`
+	ReturnsError bool          `json:"returns_error,omitempty"`
+	Order        int           `json:"order,omitempty"`
+	Entry        string        `json:"entry,omitempty"`
+	Phase        string        `json:"phase,omitempty"`
+	Health       string        `json:"health,omitempty"`
 }
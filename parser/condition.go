@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func validateCondition(pkg *packages.Package, provider *Provider) error {
+	obj, ok := pkg.Types.Scope().Lookup(provider.Condition).(*types.Func)
+	if !ok {
+		return NewValidationError("@If predicate must be a function in the provider's package", provider.Name, provider.Condition, provider.File)
+	}
+
+	sig := obj.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool]) {
+		return NewValidationError("@If predicate must have the signature func() bool", provider.Name, provider.Condition, provider.File)
+	}
+
+	return nil
+}
+
+// linkConditionalProviders moves every @If provider out of the provider
+// list and onto the unconditional provider of the same type and @Named
+// qualifier, which becomes the fallback used when no predicate holds.
+func linkConditionalProviders(metadata *Metadata) error {
+	if metadata.Root != nil && metadata.Root.Condition != "" {
+		return NewValidationError("@If cannot be used on the @Root provider", metadata.Root.Name, metadata.Root.Condition, metadata.Root.File)
+	}
+
+	fallbacks := make(map[string]*Provider)
+	for _, p := range metadata.Providers {
+		if p.Condition == "" && p.Return != nil {
+			sig := p.Return.Type.Signature() + "@" + p.Qualifier
+			if existing, ok := fallbacks[sig]; ok && existing.IsPrimary && !p.IsPrimary {
+				continue
+			}
+			fallbacks[sig] = p
+		}
+	}
+
+	providers := []*Provider{}
+	for _, p := range metadata.Providers {
+		if p.Condition == "" {
+			providers = append(providers, p)
+			continue
+		}
+
+		fallback, ok := fallbacks[p.Return.Type.Signature()+"@"+p.Qualifier]
+		if !ok {
+			return NewValidationError("@If provider requires an unconditional provider of the same type and @Named qualifier as fallback", p.Name, p.Condition, p.File)
+		}
+		fallback.Alternatives = append(fallback.Alternatives, p)
+	}
+	metadata.Providers = providers
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const conditionStore = `package store
+
+type DB struct{}
+
+// @Injectable
+// @Named replica
+func NewReplica() *DB { return &DB{} }
+
+// @Injectable
+func NewDB() *DB { return &DB{} }
+
+func UseLocal() bool { return false }
+`
+
+func TestConditionalProviderFallbackQualifier(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": conditionStore + `
+// @Injectable
+// @Named replica
+// @If UseLocal
+func NewLocalReplica() *DB { return &DB{} }
+`,
+	})
+
+	metadata, err := quietParser().Parse(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range metadata.Providers {
+		alternatives := []string{}
+		for _, alt := range p.Alternatives {
+			alternatives = append(alternatives, alt.Name)
+		}
+		want := ""
+		if p.Name == "NewReplica" {
+			want = "NewLocalReplica"
+		}
+		if got := strings.Join(alternatives, ","); got != want {
+			t.Errorf("%s alternatives = %q, want %q", p.Name, got, want)
+		}
+	}
+}
+
+func TestConditionalProviderWithoutQualifiedFallback(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": conditionStore + `
+// @Injectable
+// @Named primary
+// @If UseLocal
+func NewLocalPrimary() *DB { return &DB{} }
+`,
+	})
+
+	_, err := quietParser().Parse(dir)
+	if err == nil || !strings.Contains(err.Error(), "@If provider requires an unconditional provider") {
+		t.Fatalf("err = %v, want a missing fallback error", err)
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseContext binds the first parameter of a provider marked `@Context`,
+// which must be a context.Context, to the ctx parameter of the generated
+// root function.
+func (p *Parser) ParseContext(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider) error {
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return NewValidationError("cannot resolve provider signature", fn.Name.Name, "", provider.File)
+	}
+
+	params := obj.Type().(*types.Signature).Params()
+	if params.Len() == 0 {
+		return NewValidationError("@Context provider must take a context.Context as first parameter", fn.Name.Name, "", provider.File)
+	}
+	first := params.At(0)
+	if named, ok := first.Type().(*types.Named); !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "context" || named.Obj().Name() != "Context" {
+		return NewValidationError("@Context provider must take a context.Context as first parameter", fn.Name.Name, first.Name(), provider.File)
+	}
+
+	for _, d := range provider.Deps {
+		if d.Name == first.Name() {
+			d.IsContext = true
+			return nil
+		}
+	}
+
+	return NewValidationError("@Context parameter must be named", fn.Name.Name, "", provider.File)
+}
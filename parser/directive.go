@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+)
+
+const directivePrefix = "//dix:"
+
+// directiveNames maps the directive form of each annotation, for example
+// //dix:injectable, to its @ form.
+var directiveNames = map[string]string{
+	"injectable": "@Injectable",
+	"root":       "@Root",
+	"disable":    "@Disable",
+	"decorator":  "@Decorator",
+	"expose":     "@Expose",
+	"if":         "@If",
+	"implements": "@Implements",
+	"bind":       "@Bind",
+	"named":      "@Named",
+	"use":        "@Use",
+	"value":      "@Value",
+	"env":        "@Env",
+	"softerror":  "@SoftError",
+	"context":    "@Context",
+	"selfname":   "@SelfName",
+	"memoize":    "@Memoize",
+	"optional":   "@Optional",
+	"order":      "@Order",
+	"primary":    "@Primary",
+	"final":      "@Final",
+	"entry":      "@Entry",
+	"phase":      "@Phase",
+	"health":     "@Health",
+	"skip":       "@dix:skip",
+}
+
+// commentText returns the text of cg like CommentGroup.Text. When
+// Directives is set, `//dix:name args` lines, which Text drops as tool
+// directives, are appended in their @ form.
+func (p *Parser) commentText(cg *ast.CommentGroup) string {
+	if cg == nil {
+		return ""
+	}
+
+	text := cg.Text()
+	if !p.Directives {
+		return text
+	}
+
+	for _, c := range cg.List {
+		rest, ok := strings.CutPrefix(c.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+
+		name, args, _ := strings.Cut(rest, " ")
+		annotation, ok := directiveNames[name]
+		if !ok {
+			annotation = "@" + name
+		}
+		text += strings.TrimSpace(annotation+" "+strings.TrimSpace(args)) + "\n"
+	}
+
+	return text
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validateEntries requires @Entry names to be unique and keeps them off @If
+// providers, which are only reachable through their fallback.
+func validateEntries(metadata *Metadata) error {
+	providers := append([]*Provider{}, metadata.Providers...)
+	if metadata.Root != nil {
+		providers = append(providers, metadata.Root)
+	}
+
+	names := make(map[string]*Provider)
+	errs := []error{}
+	for _, p := range providers {
+		for _, alt := range p.Alternatives {
+			if alt.Entry != "" {
+				errs = append(errs, NewValidationError("@Entry cannot be used on an @If provider", alt.Name, alt.Entry, alt.File))
+			}
+		}
+
+		if p.Entry == "" {
+			continue
+		}
+		if first, ok := names[p.Entry]; ok {
+			errs = append(errs, NewValidationError(
+				fmt.Sprintf("@Entry %s is already used by %s.%s", p.Entry, first.PackagePath, first.Name),
+				p.Name,
+				p.Entry,
+				p.File,
+			))
+			continue
+		}
+		names[p.Entry] = p
+	}
+
+	return errors.Join(errs...)
+}
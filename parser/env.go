@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isEnvType reports whether t can be read from an environment variable:
+// string, int, bool or time.Duration.
+func isEnvType(t types.Type) bool {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+	}
+
+	basic, ok := t.(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch basic.Kind() {
+	case types.String, types.Int, types.Bool:
+		return true
+	}
+	return false
+}
+
+// ParseEnvs binds `@Env <param> <VAR>` annotations to the matching
+// parameters of provider, which then receive the value of the environment
+// variable VAR when Root runs.
+func (p *Parser) ParseEnvs(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, envs [][2]string) error {
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return NewValidationError("cannot resolve provider signature", fn.Name.Name, "", provider.File)
+	}
+	params := obj.Type().(*types.Signature).Params()
+
+	for _, env := range envs {
+		name, variable := env[0], env[1]
+
+		var param *types.Var
+		for i := 0; i < params.Len(); i++ {
+			if params.At(i).Name() == name {
+				param = params.At(i)
+			}
+		}
+
+		var dep *Dependency
+		for _, d := range provider.Deps {
+			if d.Name == name {
+				dep = d
+			}
+		}
+
+		if param == nil || dep == nil {
+			return NewValidationError("@Env refers to an unknown parameter", fn.Name.Name, name, provider.File)
+		}
+		if dep.IsValue() {
+			return NewValidationError("parameter is already bound by another annotation", fn.Name.Name, name, provider.File)
+		}
+		if !isEnvType(param.Type()) {
+			return NewValidationError("@Env parameter must be a string, int, bool or time.Duration", fn.Name.Name, name, provider.File)
+		}
+
+		dep.Env = variable
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ type ParseErrorKind string
 const (
 	ParseErrorValidation  ParseErrorKind = "validation"
 	ParseErrorPackageLoad ParseErrorKind = "package_load"
+	ParseErrorModule      ParseErrorKind = "module"
 )
 
 type ParseError struct {
@@ -64,3 +65,10 @@ func NewPackageLoadError(cause error) *ParseError {
 		Cause:   cause,
 	}
 }
+
+func NewModuleError(pkgPath string) *ParseError {
+	return &ParseError{
+		Kind:    ParseErrorModule,
+		Message: fmt.Sprintf("package %s does not belong to a module with a module path; add a `module` directive to go.mod", pkgPath),
+	}
+}
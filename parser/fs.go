@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ParseFS parses the module rooted at root in fsys, such as an embed.FS or
+// an fstest.MapFS, which must hold its go.mod. Type checking needs the go
+// tool, so the files are copied to a temporary directory that is removed
+// afterwards; provider file names are reported relative to fsys, while
+// error messages may still name the temporary copy. Imports outside the
+// module are resolved from the module cache as usual.
+func (p *Parser) ParseFS(fsys fs.FS, root string) (*Metadata, error) {
+	dir, err := os.MkdirTemp("", "dix-fs-*")
+	if err != nil {
+		return nil, NewPackageLoadError(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := copyFS(fsys, root, dir); err != nil {
+		return nil, NewPackageLoadError(err)
+	}
+
+	metadata, err := p.Parse(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		realDir = dir
+	}
+	rename := func(file string) string {
+		for _, prefix := range []string{dir, realDir} {
+			if rel, ok := strings.CutPrefix(file, prefix+string(filepath.Separator)); ok {
+				return path.Join(root, filepath.ToSlash(rel))
+			}
+		}
+		return file
+	}
+
+	var relocate func(providers []*Provider)
+	relocate = func(providers []*Provider) {
+		for _, provider := range providers {
+			if provider == nil {
+				continue
+			}
+			provider.File = rename(provider.File)
+			relocate(provider.Alternatives)
+		}
+	}
+	relocate(metadata.Providers)
+	relocate(metadata.Decorators)
+	relocate([]*Provider{metadata.Root})
+
+	return metadata, nil
+}
+
+// copyFS writes the regular files below root in fsys to dir, keeping their
+// relative paths.
+func copyFS(fsys fs.FS, root, dir string) error {
+	return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel := name
+		if root != "." {
+			rel = strings.TrimPrefix(strings.TrimPrefix(name, root), "/")
+		}
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, body, 0644)
+	})
+}
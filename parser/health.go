@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseHealth checks that `@Health <Method>` names an exported method of
+// the provider's return type with the signature func(context.Context)
+// error, and records it on provider.
+func (p *Parser) ParseHealth(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, method string) error {
+	ret := pkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)
+	if ret == nil {
+		return NewValidationError("cannot resolve provider return type", fn.Name.Name, "", provider.File)
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(ret, true, pkg.Types, method)
+	m, ok := obj.(*types.Func)
+	if !ok || !m.Exported() {
+		return NewValidationError(
+			fmt.Sprintf("@Health %s is not an exported method of %s", method, types.TypeString(ret, nil)),
+			fn.Name.Name,
+			"",
+			provider.File,
+		)
+	}
+
+	sig := m.Type().(*types.Signature)
+	params, results := sig.Params(), sig.Results()
+	if params.Len() != 1 || types.TypeString(params.At(0).Type(), nil) != "context.Context" ||
+		results.Len() != 1 || !types.Identical(results.At(0).Type(), types.Universe.Lookup("error").Type()) {
+		return NewValidationError(
+			fmt.Sprintf("@Health %s must have the signature func(context.Context) error", method),
+			fn.Name.Name,
+			"",
+			provider.File,
+		)
+	}
+
+	provider.Health = method
+	return nil
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeModule writes files, keyed by slash-separated path, below dir
+// together with the go.mod of the module example.com/app, and returns dir.
+func writeModule(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+	files["go.mod"] = "module example.com/app\n\ngo 1.25.1\n"
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// quietParser returns a Parser that discards its progress messages.
+func quietParser() *Parser {
+	p := NewParser()
+	p.Logger = NopLogger{}
+	return p
+}
+
+// providerIDs returns the package path and name of every provider.
+func providerIDs(metadata *Metadata) []string {
+	ids := []string{}
+	for _, p := range metadata.Providers {
+		ids = append(ids, p.PackagePath+"."+p.Name)
+	}
+	return ids
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// lookupInterface resolves an interface named in an annotation, either
+// unqualified from the provider's package or as <import name>.<Name> using
+// the imports of the provider's file.
+func lookupInterface(pkg *packages.Package, file *ast.File, name string) (*types.TypeName, error) {
+	scope := pkg.Types.Scope()
+	typeName := name
+
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		qualifier := name[:idx]
+		typeName = name[idx+1:]
+		scope = nil
+
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			for _, imported := range pkg.Types.Imports() {
+				if imported.Path() != path {
+					continue
+				}
+
+				local := imported.Name()
+				if spec.Name != nil {
+					local = spec.Name.Name
+				}
+				if local == qualifier {
+					scope = imported.Scope()
+				}
+			}
+		}
+
+		if scope == nil {
+			return nil, fmt.Errorf("package %s is not imported", qualifier)
+		}
+	}
+
+	obj, ok := scope.Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+	if !types.IsInterface(obj.Type()) {
+		return nil, fmt.Errorf("type %s is not an interface", name)
+	}
+
+	return obj, nil
+}
+
+func (p *Parser) ParseImplements(pkg *packages.Package, file *ast.File, fn *ast.FuncDecl, provider *Provider, names []string) error {
+	ret := pkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)
+	if ret == nil {
+		return NewValidationError("cannot resolve provider return type", fn.Name.Name, "", provider.File)
+	}
+
+	for _, name := range names {
+		obj, err := lookupInterface(pkg, file, name)
+		if err != nil {
+			return NewValidationError(fmt.Sprintf("invalid @Implements %s: %v", name, err), fn.Name.Name, "", provider.File)
+		}
+
+		iface := obj.Type().Underlying().(*types.Interface)
+		if !types.Implements(ret, iface) {
+			missing, _ := types.MissingMethod(ret, iface, true)
+			msg := fmt.Sprintf("%s does not implement %s", types.TypeString(ret, nil), name)
+			if missing != nil {
+				msg = fmt.Sprintf("%s (missing method %s)", msg, missing.Name())
+			}
+			return NewValidationError(msg, fn.Name.Name, "", provider.File)
+		}
+
+		provider.Implements = append(provider.Implements, &TypeInfo{
+			Name: obj.Name(),
+			Pkg:  obj.Pkg().Path(),
+		})
+	}
+
+	return nil
+}
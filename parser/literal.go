@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isLiteral accepts the constant forms allowed in @Value: numbers, strings,
+// runes, true/false and negated numbers.
+func isLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Name == "true" || e.Name == "false"
+	case *ast.UnaryExpr:
+		_, ok := e.X.(*ast.BasicLit)
+		return ok && (e.Op == token.SUB || e.Op == token.ADD)
+	}
+	return false
+}
+
+// ParseValues binds `@Value <param> <literal>` annotations to the matching
+// parameters of provider, checking that the literal is assignable to the
+// parameter type.
+func (p *Parser) ParseValues(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, values [][2]string) error {
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return NewValidationError("cannot resolve provider signature", fn.Name.Name, "", provider.File)
+	}
+	params := obj.Type().(*types.Signature).Params()
+
+	for _, value := range values {
+		name, literal := value[0], value[1]
+
+		var param *types.Var
+		for i := 0; i < params.Len(); i++ {
+			if params.At(i).Name() == name {
+				param = params.At(i)
+			}
+		}
+
+		var dep *Dependency
+		for _, d := range provider.Deps {
+			if d.Name == name {
+				dep = d
+			}
+		}
+
+		if param == nil || dep == nil {
+			return NewValidationError("@Value refers to an unknown parameter", fn.Name.Name, name, provider.File)
+		}
+
+		expr, err := goparser.ParseExpr(literal)
+		if err != nil || !isLiteral(expr) {
+			return NewValidationError(fmt.Sprintf("@Value %s is not a literal", literal), fn.Name.Name, name, provider.File)
+		}
+
+		tv, err := types.Eval(token.NewFileSet(), pkg.Types, token.NoPos, literal)
+		if err != nil || !types.AssignableTo(tv.Type, param.Type()) {
+			return NewValidationError(
+				fmt.Sprintf("@Value %s cannot be used as %s", literal, types.TypeString(param.Type(), nil)),
+				fn.Name.Name,
+				name,
+				provider.File,
+			)
+		}
+
+		dep.Literal = literal
+	}
+
+	return nil
+}
+
+// ParseSelfName binds `@SelfName <param>` to the string parameter param,
+// which then receives the provider's qualified name, such as "repo.NewRepo".
+func (p *Parser) ParseSelfName(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, param string) error {
+	for _, d := range provider.Deps {
+		if d.Name != param {
+			continue
+		}
+
+		obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return NewValidationError("cannot resolve provider signature", fn.Name.Name, "", provider.File)
+		}
+		params := obj.Type().(*types.Signature).Params()
+		for i := 0; i < params.Len(); i++ {
+			if params.At(i).Name() != param {
+				continue
+			}
+			if basic, ok := params.At(i).Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+				return NewValidationError("@SelfName parameter must be of a string type", fn.Name.Name, param, provider.File)
+			}
+		}
+
+		d.Literal = strconv.Quote(provider.PackageName + "." + provider.Name)
+		return nil
+	}
+
+	return NewValidationError("@SelfName refers to an unknown parameter", fn.Name.Name, param, provider.File)
+}
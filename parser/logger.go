@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Logger receives the progress messages of Parse: Debugf for skipped files
+// and directories, Infof for scanned files and Errorf for files with
+// invalid annotations. The errors themselves are returned by Parse.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// ConsoleLogger writes messages to Out with a colored [Scan] tag, as the
+// dix command does. Debugf messages are only written when Verbose is set.
+type ConsoleLogger struct {
+	Out     io.Writer
+	Verbose bool
+}
+
+func NewConsoleLogger(out io.Writer) *ConsoleLogger {
+	return &ConsoleLogger{Out: out}
+}
+
+func (l *ConsoleLogger) Debugf(format string, args ...any) {
+	if !l.Verbose {
+		return
+	}
+	l.printf("\033[33m", format, args...)
+}
+
+func (l *ConsoleLogger) Infof(format string, args ...any) {
+	l.printf("\033[32m", format, args...)
+}
+
+func (l *ConsoleLogger) Errorf(format string, args ...any) {
+	l.printf("\033[31m", format, args...)
+}
+
+func (l *ConsoleLogger) printf(color, format string, args ...any) {
+	fmt.Fprintf(l.Out, color+"[Scan]\033[0m "+format+"\n", args...)
+}
+
+// NopLogger discards every message.
+type NopLogger struct{}
+
+func (NopLogger) Debugf(format string, args ...any) {}
+func (NopLogger) Infof(format string, args ...any)  {}
+func (NopLogger) Errorf(format string, args ...any) {}
+
+// logger returns the configured Logger, or a ConsoleLogger writing to
+// standard output.
+func (p *Parser) logger() Logger {
+	if p.Logger == nil {
+		return NewConsoleLogger(os.Stdout)
+	}
+	return p.Logger
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeLogger records every message with its level.
+type fakeLogger struct {
+	messages []string
+}
+
+func (l *fakeLogger) Debugf(format string, args ...any) { l.add("debug", format, args) }
+func (l *fakeLogger) Infof(format string, args ...any)  { l.add("info", format, args) }
+func (l *fakeLogger) Errorf(format string, args ...any) { l.add("error", format, args) }
+
+func (l *fakeLogger) add(level, format string, args []any) {
+	l.messages = append(l.messages, level+": "+fmt.Sprintf(format, args...))
+}
+
+var loggedModule = map[string]string{
+	"store/store.go": "package store\n\ntype DB struct{}\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+	"store/gen.go":   "// Code generated by hand. DO NOT EDIT.\n\npackage store\n",
+	"store/skip.go":  "// @dix:skip\npackage store\n",
+	"bad/bad.go":     "package bad\n\ntype T struct{}\n\n// @Injectable\n// @Entry\nfunc NewT() *T { return &T{} }\n",
+}
+
+func TestLoggerMessages(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), loggedModule)
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &fakeLogger{}
+	p := NewParser()
+	p.Logger = logger
+	if _, err := p.Parse(dir); err == nil {
+		t.Fatal("Parse accepted the malformed annotation")
+	}
+
+	got := strings.Join(logger.messages, "\n")
+	for _, want := range []string{
+		"info: File: " + filepath.Join(dir, "store", "store.go") + " ... OK",
+		"debug: Skipped generated file: " + filepath.Join(dir, "store", "gen.go"),
+		"debug: Skipped file: " + filepath.Join(dir, "store", "skip.go"),
+		"error: File: " + filepath.Join(dir, "bad", "bad.go") + " ... FAILED",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("messages miss %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestNopLoggerIsSilent(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), loggedModule)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	p := NewParser()
+	p.Logger = NopLogger{}
+	_, parseErr := p.Parse(dir)
+
+	os.Stdout, os.Stderr = stdout, stderr
+	if parseErr == nil {
+		t.Error("Parse accepted the malformed annotation")
+	}
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) > 0 {
+		t.Errorf("NopLogger wrote %q", out)
+	}
+}
@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseMemoize marks the di.Singleton parameter param as memoized: every
+// memoized parameter of the same type in a root function then shares one
+// instance instead of receiving its own.
+func (p *Parser) ParseMemoize(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, param string) error {
+	for _, d := range provider.Deps {
+		if d.Name != param {
+			continue
+		}
+		if !d.IsSingleton {
+			return NewValidationError("@Memoize parameter must be a di.Singleton[T]", fn.Name.Name, param, provider.File)
+		}
+		d.Memoize = true
+		return nil
+	}
+
+	return NewValidationError("@Memoize refers to an unknown parameter", fn.Name.Name, param, provider.File)
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergePolicy decides what MergeMetadata does when both inputs provide the
+// same type with different functions.
+type MergePolicy string
+
+const (
+	MergeKeepFirst MergePolicy = "keep_first"
+	MergeOverride  MergePolicy = "override"
+	MergeError     MergePolicy = "error"
+)
+
+func providerID(p *Provider) string {
+	return p.PackagePath + "." + p.Name
+}
+
+// MergeMetadata combines two scans, for example of a library and of the
+// application using it. Providers are matched by return type; the same
+// function found in both scans is kept once and is never a conflict.
+// Decorators of both inputs are kept. Neither input is modified.
+func MergeMetadata(a, b *Metadata, policy MergePolicy) (*Metadata, error) {
+	switch policy {
+	case MergeKeepFirst, MergeOverride, MergeError:
+	default:
+		return nil, NewParseError(ParseErrorValidation, fmt.Sprintf("unknown merge policy %q", policy))
+	}
+
+	merged := a.Clone()
+	if merged == nil {
+		merged = new(Metadata)
+	}
+	b = b.Clone()
+	if b == nil {
+		return merged, nil
+	}
+
+	errs := []error{}
+	conflict := func(first, second *Provider) bool {
+		if providerID(first) == providerID(second) {
+			return false
+		}
+		if policy == MergeError {
+			errs = append(errs, NewValidationError(
+				fmt.Sprintf("conflicts with %s, which provides the same type", providerID(first)),
+				second.Name,
+				"",
+				second.File,
+			))
+		}
+		return policy == MergeOverride
+	}
+
+	index := make(map[string]int)
+	for i, p := range merged.Providers {
+		if p.Return != nil {
+			index[p.Return.Type.Signature()] = i
+		}
+	}
+
+	for _, p := range b.Providers {
+		if p.Return == nil {
+			merged.Providers = append(merged.Providers, p)
+			continue
+		}
+
+		sig := p.Return.Type.Signature()
+		i, ok := index[sig]
+		if !ok {
+			index[sig] = len(merged.Providers)
+			merged.Providers = append(merged.Providers, p)
+			continue
+		}
+
+		if conflict(merged.Providers[i], p) {
+			merged.Providers[i] = p
+		}
+	}
+
+	if merged.Root == nil {
+		merged.Root = b.Root
+	} else if b.Root != nil && conflict(merged.Root, b.Root) {
+		merged.Root = b.Root
+	}
+
+	decorators := make(map[string]bool)
+	for _, d := range merged.Decorators {
+		decorators[providerID(d)] = true
+	}
+	for _, d := range b.Decorators {
+		if !decorators[providerID(d)] {
+			decorators[providerID(d)] = true
+			merged.Decorators = append(merged.Decorators, d)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return merged, nil
+}
+
+// ParseRoots parses each directory, typically the roots of separate
+// modules, and merges the results in order with MergeMetadata. Every
+// directory is loaded with its own go.mod, so import paths are those of the
+// module it belongs to.
+func (p *Parser) ParseRoots(dirs []string, policy MergePolicy) (*Metadata, error) {
+	var merged *Metadata
+	for _, dir := range dirs {
+		m, err := p.Parse(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		merged, err = MergeMetadata(merged, m, policy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if merged == nil {
+		merged = new(Metadata)
+	}
+	return merged, nil
+}
@@ -1,6 +1,7 @@
 package parser
 
 type Metadata struct {
-	Providers []*Provider `json:"compositions"`
-	Root      *Provider   `json:"root"`
+	Providers  []*Provider `json:"compositions"`
+	Root       *Provider   `json:"root"`
+	Decorators []*Provider `json:"decorators"`
 }
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const receiverDepName = "receiver"
+
+// parseReceiver turns the receiver of a method provider into its first
+// dependency, so that the method is called on the instance built by the
+// provider of the receiver type. The provider is named Type.Method.
+func parseReceiver(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider) error {
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return NewValidationError("cannot resolve provider signature", fn.Name.Name, "", provider.File)
+	}
+	recv := obj.Type().(*types.Signature).Recv()
+
+	recvType := recv.Type()
+	if ptr, ok := recvType.(*types.Pointer); ok {
+		recvType = ptr.Elem()
+	}
+	named, ok := recvType.(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return NewValidationError("method provider receiver must be a non-generic named type", fn.Name.Name, "", provider.File)
+	}
+
+	name := recv.Name()
+	if name == "" || name == "_" {
+		name = receiverDepName
+	}
+
+	typeName, isPtr := parseTypeDetails(recv.Type())
+	provider.Deps = append(provider.Deps, &Dependency{
+		Name: name,
+		Type: &TypeInfo{
+			Name:      typeName,
+			Pkg:       getPackagePath(recv.Type()),
+			IsPointer: isPtr,
+		},
+	})
+	provider.Method = fn.Name.Name
+	provider.Name = named.Obj().Name() + "." + fn.Name.Name
+	if provider.ParamCount >= 0 {
+		provider.ParamCount++
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseOptional marks the parameter param as optional: when no enabled
+// provider returns its type, the zero value is passed instead of failing.
+func (p *Parser) ParseOptional(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, param string) error {
+	for _, d := range provider.Deps {
+		if d.Name != param {
+			continue
+		}
+		if d.IsValue() {
+			return NewValidationError("parameter is already bound by another annotation", fn.Name.Name, param, provider.File)
+		}
+		d.Optional = true
+		return nil
+	}
+
+	return NewValidationError("@Optional refers to an unknown parameter", fn.Name.Name, param, provider.File)
+}
@@ -1,15 +1,41 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/fatih/color"
 	"golang.org/x/tools/go/packages"
 )
 
-type Parser struct{}
+// Parser keeps no state between calls; package-level annotation regexes are
+// only read, so concurrent Parse calls are safe unless Timings is set or
+// Logger is not safe for concurrent use.
+type Parser struct {
+	FollowSymlinks bool
+
+	// Directives also accepts annotations written as Go tool directives,
+	// such as //dix:injectable.
+	Directives bool
+
+	// Exclude lists directories, relative to the scanned directory, whose
+	// packages are not scanned, in addition to the vendor, testdata and
+	// hidden directories the go tool always skips. Patterns use path.Match
+	// syntax, such as examples or internal/*/mocks.
+	Exclude []string
+
+	// Logger receives progress messages. When nil, they are written to
+	// standard output.
+	Logger Logger
+
+	// Timings, when not nil, receives the duration of each phase of Parse.
+	Timings *Timings
+}
 
 func isDixSingletonNamed(named *types.Named) bool {
 	if named == nil {
@@ -29,8 +55,24 @@ func (p *Parser) ParseProvider(pkg *packages.Package, file *ast.File, fn *ast.Fu
 	c := &Provider{
 		Name:        fn.Name.Name,
 		File:        pkg.Fset.Position(file.Package).Filename,
+		Line:        pkg.Fset.Position(fn.Pos()).Line,
 		PackagePath: pkg.PkgPath,
 		PackageName: pkg.Name,
+		ParamCount:  -1,
+	}
+
+	// Parameter count comes from go/types so that params dropped below
+	// (unnamed or unresolved types) can still be reported by the linter.
+	if obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+		if sig, ok := obj.Type().(*types.Signature); ok {
+			c.ParamCount = sig.Params().Len()
+		}
+	}
+
+	if fn.Recv != nil {
+		if err := parseReceiver(pkg, fn, c); err != nil {
+			return nil, err
+		}
 	}
 
 	if fn.Type.Params != nil {
@@ -101,39 +143,162 @@ func (p *Parser) ParseProvider(pkg *packages.Package, file *ast.File, fn *ast.Fu
 		}
 	}
 
-	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+	results := []ast.Expr{}
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			n := len(field.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, field.Type)
+			}
+		}
+	}
+
+	returnsError := len(results) == 2 && types.Identical(pkg.TypesInfo.TypeOf(results[1]), types.Universe.Lookup("error").Type())
+
+	c.SoftError = containsSoftErrorAnnotation(p.commentText(fn.Doc))
+	if c.SoftError {
+		if !returnsError {
+			return nil, NewValidationError(
+				"@SoftError provider function must return (T, error)",
+				fn.Name.Name,
+				"",
+				c.File,
+			)
+		}
+	} else if returnsError {
+		// Without @SoftError the error is returned by the root function.
+		c.ReturnsError = true
+	} else if len(results) != 1 {
 		return nil, NewValidationError(
-			"provider function must return exactly one value",
+			"provider function must return exactly one value or (T, error)",
 			fn.Name.Name,
 			"",
 			c.File,
 		)
 	}
 
-	if len(fn.Type.Results.List) > 1 || (len(fn.Type.Results.List) == 1 && len(fn.Type.Results.List[0].Names) > 1) {
+	rtv := pkg.TypesInfo.TypeOf(results[0])
+	rName, rIsPtr := parseTypeDetails(rtv)
+	rPkg := getPackagePath(rtv)
+
+	c.Return = &ReturnValue{
+		Type: &TypeInfo{
+			Name:      rName,
+			Pkg:       rPkg,
+			IsPointer: rIsPtr,
+		},
+	}
+
+	return c, nil
+}
+
+func (p *Parser) ParseExposedField(pkg *packages.Package, fn *ast.FuncDecl, source *Provider, path string) (*Provider, error) {
+	if len(fn.Type.Results.List) != 1 && !source.ReturnsError {
+		return nil, NewValidationError("cannot expose field of provider without a single return value", fn.Name.Name, path, source.File)
+	}
+
+	t := pkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)
+	if t == nil {
+		return nil, NewValidationError("cannot resolve provider return type", fn.Name.Name, path, source.File)
+	}
+
+	fields := strings.Split(path, ".")
+	for _, name := range fields {
+		if !ast.IsExported(name) {
+			return nil, NewValidationError("exposed field must be exported", fn.Name.Name, path, source.File)
+		}
+
+		obj, _, _ := types.LookupFieldOrMethod(t, true, pkg.Types, name)
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() {
+			return nil, NewValidationError(
+				fmt.Sprintf("field %s not found on %s", name, types.TypeString(t, nil)),
+				fn.Name.Name,
+				path,
+				source.File,
+			)
+		}
+		t = field.Type()
+	}
+
+	typeName, isPtr := parseTypeDetails(t)
+	return &Provider{
+		Name:        fmt.Sprintf("%s.%s", source.Name, path),
+		File:        source.File,
+		Line:        source.Line,
+		PackagePath: source.PackagePath,
+		PackageName: source.PackageName,
+		ParamCount:  1,
+		Deps: []*Dependency{
+			{
+				Name:      "source",
+				Type:      source.Return.Type,
+				Qualifier: source.Qualifier,
+			},
+		},
+		Return: &ReturnValue{
+			Type: &TypeInfo{
+				Name:      typeName,
+				Pkg:       getPackagePath(t),
+				IsPointer: isPtr,
+			},
+		},
+		FieldPath:   fields,
+		Qualifier:   source.Qualifier,
+		Annotations: []string{"@Expose " + path},
+	}, nil
+}
+
+func (p *Parser) ParseDecorator(pkg *packages.Package, file *ast.File, fn *ast.FuncDecl) (*Provider, error) {
+	fileName := pkg.Fset.Position(file.Package).Filename
+
+	obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return nil, NewValidationError("cannot resolve decorator signature", fn.Name.Name, "", fileName)
+	}
+
+	sig := obj.Type().(*types.Signature)
+	if sig.Recv() != nil || sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return nil, NewValidationError("decorator must be a function of the form func(T) T", fn.Name.Name, "", fileName)
+	}
+
+	in := sig.Params().At(0).Type()
+	out := sig.Results().At(0).Type()
+	if !types.Identical(in, out) {
 		return nil, NewValidationError(
-			"provider function must return exactly one value",
+			fmt.Sprintf("decorator must return its parameter type %s, got %s", types.TypeString(in, nil), types.TypeString(out, nil)),
 			fn.Name.Name,
 			"",
-			c.File,
+			fileName,
 		)
 	}
 
-	for _, field := range fn.Type.Results.List {
-		rtv := pkg.TypesInfo.TypeOf(field.Type)
-		rName, rIsPtr := parseTypeDetails(rtv)
-		rPkg := getPackagePath(rtv)
-
-		c.Return = &ReturnValue{
-			Type: &TypeInfo{
-				Name:      rName,
-				Pkg:       rPkg,
-				IsPointer: rIsPtr,
-			},
-		}
+	typeName, isPtr := parseTypeDetails(out)
+	typeInfo := &TypeInfo{
+		Name:      typeName,
+		Pkg:       getPackagePath(out),
+		IsPointer: isPtr,
 	}
 
-	return c, nil
+	return &Provider{
+		Name:        fn.Name.Name,
+		File:        fileName,
+		Line:        pkg.Fset.Position(fn.Pos()).Line,
+		PackagePath: pkg.PkgPath,
+		PackageName: pkg.Name,
+		ParamCount:  1,
+		Deps: []*Dependency{
+			{
+				Name: sig.Params().At(0).Name(),
+				Type: typeInfo,
+			},
+		},
+		Return:      &ReturnValue{Type: typeInfo},
+		Annotations: getAnnotationLines(p.commentText(fn.Doc)),
+	}, nil
 }
 
 func (p *Parser) Parse(dir string) (*Metadata, error) {
@@ -141,68 +306,294 @@ func (p *Parser) Parse(dir string) (*Metadata, error) {
 	cfg := &packages.Config{
 		Dir:        dir,
 		BuildFlags: []string{"-tags=dix"},
-		Mode:       packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports,
+		Mode:       packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
 	}
 
 	metadata := new(Metadata)
+	loadStart := time.Now()
 
-	pkgs, err := packages.Load(cfg, "./...")
+	patterns, err := p.loadPatterns(dir)
 	if err != nil {
-		return nil, err
+		return nil, NewPackageLoadError(err)
 	}
 
-	var parseErr error
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, NewPackageLoadError(err)
+	}
+
+	// Load problems are fatal since type information may be missing, but all
+	// of them are reported at once.
+	loadErrs := []error{}
 	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			loadErrs = append(loadErrs, NewPackageLoadError(pkgErr))
+		}
 
-		if len(pkg.Errors) > 0 {
-			return nil, NewPackageLoadError(pkg.Errors[0])
+		// Generated imports are built from module import paths, which do not
+		// exist for packages loaded outside a module or from a go.mod without
+		// a module directive.
+		if len(pkg.Errors) == 0 && (pkg.Module == nil || pkg.Module.Path == "") {
+			loadErrs = append(loadErrs, NewModuleError(pkg.PkgPath))
+		}
+	}
+	if len(loadErrs) > 0 {
+		return nil, errors.Join(loadErrs...)
+	}
+
+	p.recordPhase(func(t *Timings) { t.Load = time.Since(loadStart) })
+	annotationsStart := time.Now()
+
+	// Validation problems are collected so that every invalid annotation in
+	// the tree is reported by a single run.
+	errs := []error{}
+	funcs := make(map[string]*funcRef)
+	registered := make(map[*ast.FuncDecl]*Provider)
+	registry := []*registryEntry{}
+	for _, pkg := range pkgs {
+
+		if p.packageSkipped(pkg) {
+			p.logger().Debugf("Skipped package: %s", pkg.PkgPath)
+			continue
 		}
 
 		for _, file := range pkg.Syntax {
 
 			fileName := pkg.Fset.Position(file.Package).Filename
+			fileErrs := len(errs)
+
+			// Generated files, including dix's own output, may contain
+			// copies of annotated functions and are never scanned.
+			if ast.IsGenerated(file) {
+				p.logger().Debugf("Skipped generated file: %s", fileName)
+				continue
+			}
 
-			fmt.Printf("\033[32m[Scan]\033[0m File: %s ... ", fileName)
+			if p.containsSkipDirective(file) {
+				p.logger().Debugf("Skipped file: %s", fileName)
+				continue
+			}
+
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+					funcs[pkg.PkgPath+"."+fn.Name.Name] = &funcRef{Pkg: pkg, File: file, Fn: fn}
+				}
+			}
+			for _, cg := range file.Comments {
+				registry = append(registry, getRegistryEntries(p.commentText(cg), fileName)...)
+			}
 
 			ast.Inspect(file, func(n ast.Node) bool {
 				fn, ok := n.(*ast.FuncDecl)
 				if !ok || fn.Doc == nil {
 					return true
 				}
+				doc := p.commentText(fn.Doc)
 
-				if containsInjectableAnnotation(fn.Doc.Text()) {
+				if containsDecoratorAnnotation(doc) || containsInjectableAnnotation(doc) {
+					if err := p.validateAnnotationSyntax(pkg, fn); err != nil {
+						errs = append(errs, err)
+						return false
+					}
+				}
+
+				if containsDecoratorAnnotation(doc) {
+					d, err := p.ParseDecorator(pkg, file, fn)
+					if err != nil {
+						errs = append(errs, err)
+						return false
+					}
+					metadata.Decorators = append(metadata.Decorators, d)
+					return true
+				}
+
+				if containsInjectableAnnotation(doc) {
 					m, err := p.ParseProvider(pkg, file, fn)
 					if err != nil {
-						parseErr = err
+						errs = append(errs, err)
 						return false
 					}
 
-					if containsRootAnnotation(fn.Doc.Text()) {
+					m.Annotations = getAnnotationLines(doc)
+					registered[fn] = m
+
+					if containsContextAnnotation(doc) {
+						if err := p.ParseContext(pkg, fn, m); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if values := getValueAnnotations(doc); len(values) > 0 {
+						if err := p.ParseValues(pkg, fn, m, values); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					for _, name := range getSelfNameAnnotations(doc) {
+						if err := p.ParseSelfName(pkg, fn, m, name); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					for _, name := range getMemoizeAnnotations(doc) {
+						if err := p.ParseMemoize(pkg, fn, m, name); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if envs := getEnvAnnotations(doc); len(envs) > 0 {
+						if err := p.ParseEnvs(pkg, fn, m, envs); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					for _, name := range getOptionalAnnotations(doc) {
+						if err := p.ParseOptional(pkg, fn, m, name); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if uses := getUseAnnotations(doc); len(uses) > 0 {
+						if err := p.ParseUses(pkg, fn, m, uses); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					m.Qualifier = getNamedAnnotation(doc)
+
+					if names := getImplementsAnnotations(doc); len(names) > 0 {
+						if err := p.ParseImplements(pkg, file, fn, m, names); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if order, ok := getOrderAnnotation(doc); ok {
+						n, err := strconv.Atoi(order)
+						if err != nil {
+							errs = append(errs, NewValidationError("@Order value must be an integer", fn.Name.Name, order, m.File))
+							return false
+						}
+						m.Order = n
+					}
+
+					m.Entry = getEntryAnnotation(doc)
+					m.Phase = getPhaseAnnotation(doc)
+
+					if method := getHealthAnnotation(doc); method != "" {
+						if err := p.ParseHealth(pkg, fn, m, method); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if m.Condition = getConditionAnnotation(doc); m.Condition != "" {
+						if err := validateCondition(pkg, m); err != nil {
+							errs = append(errs, err)
+							return false
+						}
+					}
+
+					if containsRootAnnotation(doc) {
 						metadata.Root = m
 					} else {
 						metadata.Providers = append(metadata.Providers, m)
 					}
 
-					if containsDisableAnnotation(fn.Doc.Text()) {
+					if containsDisableAnnotation(doc) {
 						m.IsDisable = true
 					}
+
+					if containsPrimaryAnnotation(doc) {
+						m.IsPrimary = true
+					}
+
+					if containsFinalAnnotation(doc) {
+						m.IsFinal = true
+					}
+
+					for _, path := range getExposeAnnotations(doc) {
+						field, err := p.ParseExposedField(pkg, fn, m, path)
+						if err != nil {
+							errs = append(errs, err)
+							return false
+						}
+						field.IsDisable = m.IsDisable
+						metadata.Providers = append(metadata.Providers, field)
+					}
+
+					if names := getBindAnnotations(doc); len(names) > 0 {
+						bindings, err := p.ParseBind(pkg, file, fn, m, names)
+						if err != nil {
+							errs = append(errs, err)
+							return false
+						}
+						metadata.Providers = append(metadata.Providers, bindings...)
+					}
 				}
 				return true
 			})
-			if parseErr != nil {
-				// Close the in-progress scan line before printing fatal error output.
-				fmt.Println()
-				return nil, parseErr
+			if len(errs) > fileErrs {
+				p.logger().Errorf("File: %s ... FAILED", fileName)
+				continue
 			}
 
-			color.New(color.FgGreen).Printf("OK\n")
+			p.logger().Infof("File: %s ... OK", fileName)
 
 		}
 
 	}
 
+	p.recordPhase(func(t *Timings) { t.Annotations = time.Since(annotationsStart) })
+	resolveStart := time.Now()
+
+	if err := p.parseRegistry(metadata, registry, funcs, registered); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := linkConditionalProviders(metadata); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := validatePrimaryProviders(metadata); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := validateEntries(metadata); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := validateBindings(metadata); err != nil {
+		errs = append(errs, err)
+	}
+
+	p.recordPhase(func(t *Timings) { t.Resolve = time.Since(resolveStart) })
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
 	return metadata, nil
 }
+
+// packageSkipped reports whether the package's doc.go holds the
+// `@dix:skip` directive, which excludes the whole package from scanning.
+func (p *Parser) packageSkipped(pkg *packages.Package) bool {
+	for _, file := range pkg.Syntax {
+		if filepath.Base(pkg.Fset.Position(file.Package).Filename) == "doc.go" && p.containsSkipDirective(file) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewParser() *Parser {
 	return &Parser{}
 }
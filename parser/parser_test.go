@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWithoutGoMod(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": "package store\n\ntype DB struct{}\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+	})
+	if err := os.Remove(filepath.Join(dir, "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := quietParser().Parse(dir)
+	if err == nil {
+		t.Fatalf("Parse returned %d providers and no error", len(metadata.Providers))
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || (parseErr.Kind != ParseErrorPackageLoad && parseErr.Kind != ParseErrorModule) {
+		t.Errorf("err = %v, want a package load or module error", err)
+	}
+}
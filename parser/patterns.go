@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// loadPatterns returns the package patterns passed to packages.Load. The go
+// tool does not follow symlinked directories when expanding "./...", so each
+// symlinked directory below dir is either scanned too or reported as
+// skipped, depending on FollowSymlinks. When Exclude is set, every remaining
+// package directory is listed instead of "./...", so that excluded packages
+// are not even loaded. Directories behind symlinks are always listed, and
+// every directory is scanned once under the first path it was reached by,
+// so a symlink into a directory already scanned adds nothing.
+func (p *Parser) loadPatterns(dir string) ([]string, error) {
+	patterns := []string{"./..."}
+	if len(p.Exclude) > 0 {
+		patterns = nil
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := make(map[string]bool)
+
+	var walk func(path string, listDirs bool) error
+	walk = func(path string, listDirs bool) error {
+		links := []string{}
+		err := filepath.WalkDir(path, func(current string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name := d.Name()
+			if current != path && d.IsDir() && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+
+			rel, err := filepath.Rel(root, current)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			if d.IsDir() && p.excluded(rel) {
+				p.logger().Debugf("Excluded directory: %s", rel)
+				return filepath.SkipDir
+			}
+
+			if d.IsDir() {
+				real, err := filepath.EvalSymlinks(current)
+				if err != nil {
+					return err
+				}
+				if visited[real] {
+					return filepath.SkipDir
+				}
+				visited[real] = true
+
+				if listDirs && hasBuildableFiles(current) {
+					if rel != "." {
+						rel = "./" + rel
+					}
+					patterns = append(patterns, rel)
+				}
+				return nil
+			}
+
+			if d.Type()&fs.ModeSymlink == 0 {
+				return nil
+			}
+
+			info, err := os.Stat(current)
+			if err != nil || !info.IsDir() || p.excluded(rel) {
+				return nil
+			}
+
+			if !p.FollowSymlinks {
+				p.logger().Debugf("Skipped symlinked directory: %s", rel)
+				return nil
+			}
+			links = append(links, current)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Symlinks are followed once the tree holding them is walked, so
+		// that a link to a directory of that tree finds it visited. The
+		// trailing separator makes WalkDir descend into the target.
+		for _, link := range links {
+			if err := walk(link+string(filepath.Separator), true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, len(p.Exclude) > 0); err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no Go package left to scan in %s after applying exclude", dir)
+	}
+	return patterns, nil
+}
+
+// hasBuildableFiles reports whether dir holds Go files that are built with
+// the dix tag, which the go tool requires of every directory named
+// explicitly in a pattern.
+func hasBuildableFiles(dir string) bool {
+	ctx := build.Default
+	ctx.BuildTags = append(ctx.BuildTags, "dix")
+	_, err := ctx.ImportDir(dir, 0)
+
+	var noGo *build.NoGoError
+	return !errors.As(err, &noGo)
+}
+
+// excluded reports whether the directory rel, relative to the scanned
+// directory, or one of its parents matches a pattern of Exclude.
+func (p *Parser) excluded(rel string) bool {
+	if rel == "." {
+		return false
+	}
+
+	for _, pattern := range p.Exclude {
+		pattern = strings.Trim(path.Clean(filepath.ToSlash(pattern)), "/")
+		for prefix := rel; prefix != "."; prefix = path.Dir(prefix) {
+			if ok, _ := path.Match(pattern, prefix); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
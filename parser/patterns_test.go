@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFollowSymlinksScansEachDirectoryOnce(t *testing.T) {
+	ext := writeModule(t, t.TempDir(), map[string]string{
+		"ext.go": "package ext\n\ntype Ext struct{}\n\n// @Injectable\nfunc NewExt() *Ext { return &Ext{} }\n",
+	})
+	os.Remove(filepath.Join(ext, "go.mod"))
+
+	root := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": "package store\n\ntype DB struct{}\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+	})
+	links := map[string]string{
+		"alias":      filepath.Join(root, "store"),
+		"nested/ext": ext,
+		"ext2":       ext,
+		"store/loop": root,
+	}
+	for link, target := range links {
+		path := filepath.Join(root, filepath.FromSlash(link))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	p := quietParser()
+	p.FollowSymlinks = true
+	metadata, err := p.Parse(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := providerIDs(metadata)
+	slices.Sort(got)
+	want := []string{"example.com/app/ext2.NewExt", "example.com/app/store.NewDB"}
+	if !slices.Equal(got, want) {
+		t.Errorf("providers = %v, want %v", got, want)
+	}
+}
+
+func TestSkippedSymlinksAreVerbose(t *testing.T) {
+	root := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": "package store\n\ntype DB struct{}\n\n// @Injectable\nfunc NewDB() *DB { return &DB{} }\n",
+	})
+	if err := os.Symlink(filepath.Join(root, "store"), filepath.Join(root, "alias")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	for _, verbose := range []bool{false, true} {
+		var out bytes.Buffer
+		p := NewParser()
+		p.Logger = &ConsoleLogger{Out: &out, Verbose: verbose}
+		if _, err := p.Parse(root); err != nil {
+			t.Fatal(err)
+		}
+
+		reported := strings.Contains(out.String(), "Skipped symlinked directory: alias")
+		if reported != verbose {
+			t.Errorf("verbose %t: skipped symlink reported: %t\n%s", verbose, reported, out.String())
+		}
+	}
+}
+
+func TestSkippedDirectories(t *testing.T) {
+	provider := func(pkg string) string {
+		return "package " + pkg + "\n\ntype T struct{}\n\n// @Injectable\nfunc NewT() *T { return &T{} }\n"
+	}
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go":                provider("store"),
+		"vendor/example.com/lib/lib.go": provider("lib"),
+		"store/vendor/nested/nested.go": provider("nested"),
+		"testdata/fixture/fixture.go":   provider("fixture"),
+		".hidden/hidden.go":             provider("hidden"),
+		"_old/old.go":                   provider("old"),
+		"examples/demo/demo.go":         provider("demo"),
+		"internal/api/mocks/mocks.go":   provider("mocks"),
+		"internal/api/api.go":           provider("api"),
+		"vendor/modules.txt":            "",
+	})
+
+	tests := []struct {
+		exclude []string
+		want    []string
+	}{
+		{
+			want: []string{"example.com/app/examples/demo.NewT", "example.com/app/internal/api.NewT", "example.com/app/internal/api/mocks.NewT", "example.com/app/store.NewT"},
+		},
+		{
+			exclude: []string{"examples", "internal/*/mocks"},
+			want:    []string{"example.com/app/internal/api.NewT", "example.com/app/store.NewT"},
+		},
+	}
+	for _, tt := range tests {
+		p := quietParser()
+		p.Exclude = tt.exclude
+		metadata, err := p.Parse(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := providerIDs(metadata)
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("exclude %v: providers = %v, want %v", tt.exclude, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validatePrimaryProviders allows at most one @Primary provider per type and
+// @Named qualifier.
+func validatePrimaryProviders(metadata *Metadata) error {
+	primaries := make(map[string]*Provider)
+	errs := []error{}
+
+	for _, p := range metadata.Providers {
+		if !p.IsPrimary || p.Return == nil {
+			continue
+		}
+
+		sig := p.Return.Type.Signature() + "@" + p.Qualifier
+		if first, ok := primaries[sig]; ok {
+			errs = append(errs, NewValidationError(
+				fmt.Sprintf("@Primary is also set on %s.%s, which provides the same type", first.PackagePath, first.Name),
+				p.Name,
+				"",
+				p.File,
+			))
+			continue
+		}
+		primaries[sig] = p
+	}
+
+	return errors.Join(errs...)
+}
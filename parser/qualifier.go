@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseUses binds `@Use <param> <name>` annotations to the matching
+// parameters of provider, which are then resolved from the provider of
+// their type declared with `@Named <name>`.
+func (p *Parser) ParseUses(pkg *packages.Package, fn *ast.FuncDecl, provider *Provider, uses [][2]string) error {
+	for _, use := range uses {
+		name, qualifier := use[0], use[1]
+
+		var dep *Dependency
+		for _, d := range provider.Deps {
+			if d.Name == name {
+				dep = d
+			}
+		}
+
+		if dep == nil {
+			return NewValidationError("@Use refers to an unknown parameter", fn.Name.Name, name, provider.File)
+		}
+		if dep.IsValue() {
+			return NewValidationError("parameter is already bound by another annotation", fn.Name.Name, name, provider.File)
+		}
+		// Repeating the same @Use is harmless, for example when one
+		// comes from a //dix:use directive.
+		if dep.Qualifier == qualifier {
+			continue
+		}
+		if dep.Qualifier != "" {
+			return NewValidationError(fmt.Sprintf("parameter has conflicting @Use annotations %s and %s", dep.Qualifier, qualifier), fn.Name.Name, name, provider.File)
+		}
+
+		dep.Qualifier = qualifier
+	}
+
+	return nil
+}
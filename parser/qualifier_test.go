@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const qualifierStore = `package store
+
+type DB struct{}
+
+// @Injectable
+// @Named primary
+func NewPrimary() *DB { return &DB{} }
+
+// @Injectable
+// @Named replica
+func NewReplica() *DB { return &DB{} }
+
+type App struct{}
+`
+
+// TestMergedUses repeats the @Use of one parameter, once as a directive,
+// and expects a single qualifier per parameter.
+func TestMergedUses(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": qualifierStore + `
+// @Injectable
+// @Root
+// @Use write primary
+// @Use read replica
+// @Use write primary
+//dix:use read replica
+func NewApp(write *DB, read *DB) *App { return &App{} }
+`,
+	})
+
+	p := quietParser()
+	p.Directives = true
+	metadata, err := p.Parse(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qualifiers := map[string]string{}
+	for _, dep := range metadata.Root.Deps {
+		qualifiers[dep.Name] = dep.Qualifier
+	}
+	if qualifiers["write"] != "primary" || qualifiers["read"] != "replica" {
+		t.Errorf("qualifiers = %v, want write=primary and read=replica", qualifiers)
+	}
+}
+
+func TestConflictingUses(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"store/store.go": qualifierStore + `
+// @Injectable
+// @Root
+// @Use db primary
+// @Use db replica
+func NewApp(db *DB) *App { return &App{} }
+`,
+	})
+
+	_, err := quietParser().Parse(dir)
+	if err == nil || !strings.Contains(err.Error(), "parameter has conflicting @Use annotations primary and replica") {
+		t.Fatalf("err = %v, want a conflicting @Use error", err)
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type registryEntry struct {
+	Kind string
+	Ref  string
+	File string
+}
+
+type funcRef struct {
+	Pkg  *packages.Package
+	File *ast.File
+	Fn   *ast.FuncDecl
+}
+
+func getRegistryEntries(comment string, file string) []*registryEntry {
+	entries := []*registryEntry{}
+	for _, m := range registryRegex.FindAllStringSubmatch(comment, -1) {
+		entries = append(entries, &registryEntry{
+			Kind: m[1],
+			Ref:  m[2],
+			File: file,
+		})
+	}
+	return entries
+}
+
+// parseRegistry registers functions referenced by fully-qualified name
+// (`@Injectable example.com/app/repo.NewRepo`) from comments anywhere in the
+// scanned tree, typically a central dix.go at the module root.
+func (p *Parser) parseRegistry(metadata *Metadata, entries []*registryEntry, funcs map[string]*funcRef, registered map[*ast.FuncDecl]*Provider) error {
+	for _, entry := range entries {
+		idx := strings.LastIndex(entry.Ref, ".")
+		if idx <= 0 || idx == len(entry.Ref)-1 {
+			return NewValidationError("registry reference must have the form <package path>.<Func>", entry.Ref, "", entry.File)
+		}
+
+		ref, ok := funcs[entry.Ref]
+		if !ok {
+			return NewValidationError("registry reference does not match any top-level function", entry.Ref, "", entry.File)
+		}
+
+		m, ok := registered[ref.Fn]
+		if !ok {
+			var err error
+			m, err = p.ParseProvider(ref.Pkg, ref.File, ref.Fn)
+			if err != nil {
+				return err
+			}
+			registered[ref.Fn] = m
+			metadata.Providers = append(metadata.Providers, m)
+		}
+		m.Annotations = append(m.Annotations, "@"+entry.Kind+" "+entry.Ref)
+
+		switch entry.Kind {
+		case "Root":
+			for i, provider := range metadata.Providers {
+				if provider == m {
+					metadata.Providers = append(metadata.Providers[:i], metadata.Providers[i+1:]...)
+					break
+				}
+			}
+			metadata.Root = m
+		case "Disable":
+			m.IsDisable = true
+		}
+	}
+
+	return nil
+}
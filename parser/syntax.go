@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type annotationForm struct {
+	regex *regexp.Regexp
+	usage string
+}
+
+// annotationForms lists the annotations that take a fixed form on a
+// provider. A line naming one of them in any other form is rejected rather
+// than silently ignored.
+var annotationForms = map[string][]annotationForm{
+	"@Injectable": {{injectableRegex, "@Injectable"}, {registryRegex, "@Injectable pkg.Func"}},
+	"@Root":       {{rootRegex, "@Root"}, {registryRegex, "@Root pkg.Func"}},
+	"@Disable":    {{disableRegex, "@Disable"}, {registryRegex, "@Disable pkg.Func"}},
+	"@Decorator":  {{decoratorRegex, "@Decorator"}},
+	"@Primary":    {{primaryRegex, "@Primary"}},
+	"@Final":      {{finalRegex, "@Final"}},
+	"@SoftError":  {{softErrorRegex, "@SoftError"}},
+	"@Context":    {{contextRegex, "@Context"}},
+	"@Order":      {{orderRegex, "@Order <integer>"}},
+	"@SelfName":   {{selfNameRegex, "@SelfName <param>"}},
+	"@Memoize":    {{memoizeRegex, "@Memoize <param>"}},
+	"@Optional":   {{optionalRegex, "@Optional <param>"}},
+	"@Entry":      {{entryRegex, "@Entry <Name>"}},
+	"@Health":     {{healthRegex, "@Health <Method>"}},
+	"@Phase":      {{phaseRegex, "@Phase <name>"}},
+	"@If":         {{conditionRegex, "@If <Func>"}},
+	"@Expose":     {{exposeRegex, "@Expose <Field>"}},
+	"@Implements": {{implementsRegex, "@Implements <Interface>"}},
+	"@Bind":       {{bindRegex, "@Bind <Interface>"}},
+	"@Named":      {{namedRegex, "@Named <name>"}},
+	"@Use":        {{useRegex, "@Use <param> <name>"}},
+	"@Value":      {{valueRegex, "@Value <param> <literal>"}},
+	"@Env":        {{envRegex, "@Env <param> <VARIABLE>"}},
+}
+
+// validateAnnotationSyntax reports every annotation line of fn's doc
+// comment that names a known annotation but does not match its form, such
+// as `@Entry` without a name or `@Value port` without a literal.
+func (p *Parser) validateAnnotationSyntax(pkg *packages.Package, fn *ast.FuncDecl) error {
+	errs := []error{}
+	for _, line := range getAnnotationLines(p.commentText(fn.Doc)) {
+		keyword, _, _ := strings.Cut(strings.Join(strings.Fields(line), " "), " ")
+		forms, ok := annotationForms[keyword]
+		if !ok {
+			continue
+		}
+
+		matched := false
+		for _, form := range forms {
+			if form.regex.MatchString(line) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		usage := []string{}
+		for _, form := range forms {
+			usage = append(usage, "`"+form.usage+"`")
+		}
+		errs = append(errs, NewValidationError(
+			fmt.Sprintf("malformed annotation %q, expected %s", line, strings.Join(usage, " or ")),
+			fn.Name.Name,
+			"",
+			annotationPosition(pkg, fn.Doc, line),
+		))
+	}
+	return errors.Join(errs...)
+}
+
+// annotationPosition returns file:line of the comment holding line, or the
+// position of the comment group when it came from a directive.
+func annotationPosition(pkg *packages.Package, doc *ast.CommentGroup, line string) string {
+	pos := doc.Pos()
+	for _, c := range doc.List {
+		if strings.Contains(c.Text, line) {
+			pos = c.Pos()
+			break
+		}
+	}
+	position := pkg.Fset.Position(pos)
+	return fmt.Sprintf("%s:%d", position.Filename, position.Line)
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const annotatedServer = `package server
+
+type DB struct{}
+
+// @Injectable
+// @Named primary
+func NewDB() *DB { return &DB{} }
+
+type Server struct{}
+
+// @Injectable
+// @Root
+// @Use db primary
+// @Value port 8080
+// @Value name "api"
+func NewServer(db *DB, port int, name string) *Server { return &Server{} }
+`
+
+// TestCRLFAnnotations parses the same file with LF and CRLF line endings and
+// expects the same providers.
+func TestCRLFAnnotations(t *testing.T) {
+	parse := func(src string) string {
+		t.Helper()
+		dir := writeModule(t, t.TempDir(), map[string]string{"server/server.go": src})
+		metadata, err := quietParser().Parse(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// File names differ between the two directories.
+		for _, p := range append(metadata.Providers, metadata.Root) {
+			p.File = ""
+		}
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	lf := parse(annotatedServer)
+	crlf := parse(strings.ReplaceAll(annotatedServer, "\n", "\r\n"))
+	if crlf != lf {
+		t.Errorf("CRLF metadata differs:\n%s\nwant\n%s", crlf, lf)
+	}
+	if strings.Contains(crlf, `\r`) {
+		t.Errorf("CRLF metadata holds a carriage return:\n%s", crlf)
+	}
+	for _, want := range []string{`"8080"`, `"\"api\""`, `"primary"`} {
+		if !strings.Contains(lf, want) {
+			t.Errorf("metadata misses %s:\n%s", want, lf)
+		}
+	}
+}
+
+func TestMalformedAnnotations(t *testing.T) {
+	tests := []struct {
+		line  string
+		usage string
+	}{
+		{"@Entry", "`@Entry <Name>`"},
+		{"@Primary true", "`@Primary`"},
+		{"@Order", "`@Order <integer>`"},
+		{"@Value port", "`@Value <param> <literal>`"},
+		{"@Env port", "`@Env <param> <VARIABLE>`"},
+		{"@Use db", "`@Use <param> <name>`"},
+		{"@Named", "`@Named <name>`"},
+		{"@If", "`@If <Func>`"},
+		{"@Memoize", "`@Memoize <param>`"},
+		{"@Optional", "`@Optional <param>`"},
+		{"@Root now please", "`@Root` or `@Root pkg.Func`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			dir := writeModule(t, t.TempDir(), map[string]string{
+				"server/server.go": "package server\n\ntype Server struct{}\n\n// @Injectable\n// " + tt.line + "\nfunc NewServer(port int) *Server { return &Server{} }\n",
+			})
+
+			_, err := quietParser().Parse(dir)
+			want := fmt.Sprintf("malformed annotation %q, expected %s [fn=NewServer]", tt.line, tt.usage)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Fatalf("err = %v, want %s", err, want)
+			}
+			if !strings.Contains(err.Error(), "server.go:6)") {
+				t.Errorf("err = %v, want the position server.go:6", err)
+			}
+		})
+	}
+}
+
+func TestMalformedAnnotationsAllReported(t *testing.T) {
+	dir := writeModule(t, t.TempDir(), map[string]string{
+		"server/server.go": "package server\n\ntype Server struct{}\n\n// @Injectable\n// @Value port\n// @Use db\nfunc NewServer(port int) *Server { return &Server{} }\n",
+	})
+
+	_, err := quietParser().Parse(dir)
+	if err == nil {
+		t.Fatal("Parse returned no error")
+	}
+	for _, line := range []string{`"@Value port"`, `"@Use db"`} {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("err = %v, want %s reported", err, line)
+		}
+	}
+}
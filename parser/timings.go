@@ -0,0 +1,21 @@
+package parser
+
+import "time"
+
+// Timings breaks the duration of Parse down by phase.
+type Timings struct {
+	// Load covers go/packages loading and type checking, usually the
+	// largest share.
+	Load time.Duration
+	// Annotations covers reading provider, decorator and registry comments.
+	Annotations time.Duration
+	// Resolve covers registry references, @If fallbacks and @Primary checks.
+	Resolve time.Duration
+}
+
+// recordPhase applies set to Timings when they were requested.
+func (p *Parser) recordPhase(set func(t *Timings)) {
+	if p.Timings != nil {
+		set(p.Timings)
+	}
+}
@@ -1,14 +1,38 @@
 package parser
 
 import (
+	"go/ast"
 	"go/types"
 	"regexp"
+	"strings"
 )
 
 var rootRegex = regexp.MustCompile(`(?m)^@Root\s*$`)
 var singletonRegex = regexp.MustCompile(`(?m)^@Singleton\s*$`)
 var disableRegex = regexp.MustCompile(`(?m)^@Disable\s*$`)
 var injectableRegex = regexp.MustCompile(`(?m)^@Injectable\s*$`)
+var finalRegex = regexp.MustCompile(`(?m)^@Final\s*$`)
+var primaryRegex = regexp.MustCompile(`(?m)^@Primary\s*$`)
+var decoratorRegex = regexp.MustCompile(`(?m)^@Decorator\s*$`)
+var softErrorRegex = regexp.MustCompile(`(?m)^@SoftError\s*$`)
+var contextRegex = regexp.MustCompile(`(?m)^@Context\s*$`)
+var orderRegex = regexp.MustCompile(`(?m)^@Order[ \t]+(\S+)\s*$`)
+var selfNameRegex = regexp.MustCompile(`(?m)^@SelfName[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var optionalRegex = regexp.MustCompile(`(?m)^@Optional[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var memoizeRegex = regexp.MustCompile(`(?m)^@Memoize[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var entryRegex = regexp.MustCompile(`(?m)^@Entry[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var healthRegex = regexp.MustCompile(`(?m)^@Health[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var phaseRegex = regexp.MustCompile(`(?m)^@Phase[ \t]+([A-Za-z_][A-Za-z0-9_-]*)\s*$`)
+var conditionRegex = regexp.MustCompile(`(?m)^@If[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var implementsRegex = regexp.MustCompile(`(?m)^@Implements[ \t]+(\S+)\s*$`)
+var valueRegex = regexp.MustCompile(`(?m)^@Value[ \t]+([A-Za-z_][A-Za-z0-9_]*)[ \t]+(.+?)\s*$`)
+var envRegex = regexp.MustCompile(`(?m)^@Env[ \t]+([A-Za-z_][A-Za-z0-9_]*)[ \t]+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+var skipRegex = regexp.MustCompile(`(?m)^@dix:skip\s*$`)
+var registryRegex = regexp.MustCompile(`(?m)^@(Injectable|Root|Disable)[ \t]+(\S+)\s*$`)
+var namedRegex = regexp.MustCompile(`(?m)^@Named[ \t]+([A-Za-z_][A-Za-z0-9_-]*)\s*$`)
+var useRegex = regexp.MustCompile(`(?m)^@Use[ \t]+([A-Za-z_][A-Za-z0-9_]*)[ \t]+([A-Za-z_][A-Za-z0-9_-]*)\s*$`)
+var bindRegex = regexp.MustCompile(`(?m)^@Bind[ \t]+(\S+)\s*$`)
+var exposeRegex = regexp.MustCompile(`(?m)^@Expose[ \t]+(\S+)\s*$`)
 
 func getPackagePath(t types.Type) string {
 	switch t := t.(type) {
@@ -60,3 +84,165 @@ func containsSingletonAnnotation(comment string) bool {
 func containsDisableAnnotation(comment string) bool {
 	return disableRegex.MatchString(comment)
 }
+
+func containsPrimaryAnnotation(comment string) bool {
+	return primaryRegex.MatchString(comment)
+}
+
+func containsFinalAnnotation(comment string) bool {
+	return finalRegex.MatchString(comment)
+}
+
+func containsDecoratorAnnotation(comment string) bool {
+	return decoratorRegex.MatchString(comment)
+}
+
+func containsSoftErrorAnnotation(comment string) bool {
+	return softErrorRegex.MatchString(comment)
+}
+
+func containsContextAnnotation(comment string) bool {
+	return contextRegex.MatchString(comment)
+}
+
+func getExposeAnnotations(comment string) []string {
+	paths := []string{}
+	for _, m := range exposeRegex.FindAllStringSubmatch(comment, -1) {
+		paths = append(paths, m[1])
+	}
+	return paths
+}
+
+func getAnnotationLines(comment string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func getEntryAnnotation(comment string) string {
+	m := entryRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func getHealthAnnotation(comment string) string {
+	m := healthRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func getPhaseAnnotation(comment string) string {
+	m := phaseRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func getConditionAnnotation(comment string) string {
+	m := conditionRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func getImplementsAnnotations(comment string) []string {
+	names := []string{}
+	for _, m := range implementsRegex.FindAllStringSubmatch(comment, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func getBindAnnotations(comment string) []string {
+	names := []string{}
+	for _, m := range bindRegex.FindAllStringSubmatch(comment, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func getNamedAnnotation(comment string) string {
+	m := namedRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func getUseAnnotations(comment string) [][2]string {
+	uses := [][2]string{}
+	for _, m := range useRegex.FindAllStringSubmatch(comment, -1) {
+		uses = append(uses, [2]string{m[1], m[2]})
+	}
+	return uses
+}
+
+func getValueAnnotations(comment string) [][2]string {
+	values := [][2]string{}
+	for _, m := range valueRegex.FindAllStringSubmatch(comment, -1) {
+		values = append(values, [2]string{m[1], m[2]})
+	}
+	return values
+}
+
+func getEnvAnnotations(comment string) [][2]string {
+	envs := [][2]string{}
+	for _, m := range envRegex.FindAllStringSubmatch(comment, -1) {
+		envs = append(envs, [2]string{m[1], m[2]})
+	}
+	return envs
+}
+
+func getOrderAnnotation(comment string) (string, bool) {
+	m := orderRegex.FindStringSubmatch(comment)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func getSelfNameAnnotations(comment string) []string {
+	names := []string{}
+	for _, m := range selfNameRegex.FindAllStringSubmatch(comment, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func getOptionalAnnotations(comment string) []string {
+	names := []string{}
+	for _, m := range optionalRegex.FindAllStringSubmatch(comment, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func getMemoizeAnnotations(comment string) []string {
+	names := []string{}
+	for _, m := range memoizeRegex.FindAllStringSubmatch(comment, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// containsSkipDirective reports whether any comment of file holds the
+// `@dix:skip` directive.
+func (p *Parser) containsSkipDirective(file *ast.File) bool {
+	for _, cg := range file.Comments {
+		if skipRegex.MatchString(p.commentText(cg)) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,9 +6,24 @@ type Dependency struct {
 	Name        string    `json:"name"`
 	Type        *TypeInfo `json:"type"`
 	IsSingleton bool      `json:"is_sng"`
+	Literal     string    `json:"literal,omitempty"`
+	Env         string    `json:"env,omitempty"`
+	Qualifier   string    `json:"qualifier,omitempty"`
+	IsContext   bool      `json:"is_ctx,omitempty"`
+	Memoize     bool      `json:"memoize,omitempty"`
+	Optional    bool      `json:"optional,omitempty"`
+}
+
+// IsValue reports whether the dependency is bound by @Value, @SelfName,
+// @Env or @Context instead of being resolved from a provider.
+func (d *Dependency) IsValue() bool {
+	return d.Literal != "" || d.Env != "" || d.IsContext
 }
 
 func (d *Dependency) String() string {
+	if d.Qualifier != "" {
+		return fmt.Sprintf(" %s (%s, @Named %s)", d.Name, d.Type.String(), d.Qualifier)
+	}
 	return fmt.Sprintf(" %s (%s)", d.Name, d.Type.String())
 }
 